@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const unassignedGroup = "" // Ключ группы задач без исполнителя
+
+// setAssignee назначает задаче исполнителя, пустое имя снимает назначение
+func setAssignee(tl *TodoList, id int, assignee string) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	tl.Tasks[index].Assignee = strings.TrimSpace(assignee)
+	return nil
+}
+
+// completedByAssignee считает выполненные задачи по исполнителям
+// Задачи без исполнителя попадают в группу unassignedGroup
+func completedByAssignee(tl *TodoList) map[string]int {
+	counts := make(map[string]int)
+	for _, task := range tl.Tasks {
+		if task.Done {
+			counts[task.Assignee]++
+		}
+	}
+	return counts
+}
+
+// assigneeCounts считает задачи по исполнителям
+// Задачи без исполнителя не учитываются
+func assigneeCounts(tl *TodoList) map[string]int {
+	counts := make(map[string]int)
+	for _, task := range tl.Tasks {
+		if task.Assignee != unassignedGroup {
+			counts[task.Assignee]++
+		}
+	}
+	return counts
+}
+
+// printCompletedByAssignee выводит число выполненных задач по исполнителям, от большего к меньшему
+func printCompletedByAssignee(tl *TodoList, w io.Writer) {
+	counts := completedByAssignee(tl)
+	if len(counts) == 0 {
+		fmt.Fprintln(w, msg("Нет выполненных задач"))
+		return
+	}
+
+	fmt.Fprintln(w, msg("Выполнено задач по исполнителям:"))
+	for _, p := range topN(counts, 0) {
+		label := p.Key
+		if p.Key == unassignedGroup {
+			label = msg("без исполнителя")
+		}
+		fmt.Fprintf(w, "%s: %d\n", label, p.Count)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"maps"
+	"testing"
+)
+
+func TestCompletedByAssignee(t *testing.T) {
+	tests := []struct {
+		name  string
+		tasks []Task
+		want  map[string]int
+	}{
+		{"empty", nil, map[string]int{}},
+		{"only pending", []Task{{Id: 1, Assignee: "anna"}}, map[string]int{}},
+		{"with unassigned", []Task{
+			{Id: 1, Done: true, Assignee: "anna"},
+			{Id: 2, Done: true, Assignee: "anna"},
+			{Id: 3, Done: true},
+			{Id: 4, Done: true, Assignee: "ivan"},
+			{Id: 5, Assignee: "ivan"},
+			{Id: 6},
+		}, map[string]int{"anna": 2, unassignedGroup: 1, "ivan": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := completedByAssignee(&TodoList{Tasks: tt.tasks}); !maps.Equal(got, tt.want) {
+				t.Errorf("completedByAssignee = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintCompletedByAssignee(t *testing.T) {
+	tl := &TodoList{Tasks: []Task{
+		{Id: 1, Done: true},
+		{Id: 2, Done: true, Assignee: "anna"},
+		{Id: 3, Done: true, Assignee: "anna"},
+		{Id: 4, Done: true, Assignee: "anna"},
+		{Id: 5, Done: true},
+		{Id: 6, Done: true, Assignee: "ivan"},
+	}}
+
+	var buf bytes.Buffer
+	printCompletedByAssignee(tl, &buf)
+	if want := "Выполнено задач по исполнителям:\nanna: 3\nбез исполнителя: 2\nivan: 1\n"; buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	printCompletedByAssignee(&TodoList{Tasks: []Task{{Id: 1}}}, &buf)
+	if buf.String() != "Нет выполненных задач\n" {
+		t.Errorf("empty output = %q", buf.String())
+	}
+}
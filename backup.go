@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const backupLayout = "20060102-150405" // Формат времени в имени файла резервной копии
+
+// backupPath возвращает путь резервной копии профиля: <профиль>.<время>.json.bak
+func backupPath(baseDir, profile string, now time.Time) string {
+	return filepath.Join(baseDir, profile+"."+now.Format(backupLayout)+".json.bak")
+}
+
+// createBackup копирует файл профиля в резервную копию с текущим временем в имени
+func createBackup(baseDir, profile string, now time.Time) (string, error) {
+	data, err := os.ReadFile(profilePath(baseDir, profile))
+	if err != nil {
+		return "", err
+	}
+
+	path := backupPath(baseDir, profile, now)
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return "", fmt.Errorf(msg("не удалось записать %s: %w"), path, err)
+	}
+	return path, nil
+}
+
+// listBackups возвращает резервные копии профиля от старых к новым
+// Учитываются только файлы, время в имени которых соответствует backupLayout
+func listBackups(baseDir, profile string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(baseDir, profile+".*.json.bak"))
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []string
+	for _, path := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), profile+"."), ".json.bak")
+		if _, err := time.Parse(backupLayout, stamp); err == nil {
+			backups = append(backups, path)
+		}
+	}
+
+	// Время в имени записано от старших разрядов к младшим, поэтому порядок имён совпадает с порядком времени
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// rotateBackups удаляет старые резервные копии профиля, оставляя keep самых новых
+// Файл самого профиля не затрагивается. Возвращает пути удалённых копий
+func rotateBackups(baseDir, profile string, keep int) ([]string, error) {
+	if keep < 0 {
+		return nil, errors.New(msg("количество копий не может быть отрицательным"))
+	}
+
+	backups, err := listBackups(baseDir, profile)
+	if err != nil {
+		return nil, err
+	}
+	if len(backups) <= keep {
+		return nil, nil
+	}
+
+	var removed []string
+	for _, path := range backups[:len(backups)-keep] {
+		if err := os.Remove(path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
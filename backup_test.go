@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRotateBackups(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{profilePath(dir, "tasks"), filepath.Join(dir, "tasks.notes.json.bak"), profilePath(dir, "work")}
+	var backups []string
+	for i := range 5 {
+		path := backupPath(dir, "tasks", testNow.Add(time.Duration(i)*time.Hour))
+		backups = append(backups, path)
+		files = append(files, path)
+	}
+	files = append(files, backupPath(dir, "work", testNow))
+	for _, path := range files {
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := rotateBackups(dir, "tasks", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(removed, backups[:3]) {
+		t.Errorf("removed = %v, want %v", removed, backups[:3])
+	}
+
+	remaining, err := listBackups(dir, "tasks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(remaining, backups[3:]) {
+		t.Errorf("remaining = %v, want %v", remaining, backups[3:])
+	}
+	kept := []string{files[0], files[1], files[2], backupPath(dir, "work", testNow)}
+	for _, path := range kept {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s removed: %v", filepath.Base(path), err)
+		}
+	}
+}
+
+func TestRotateBackupsKeepAll(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(backupPath(dir, "tasks", testNow), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if removed, err := rotateBackups(dir, "tasks", 3); err != nil || len(removed) != 0 {
+		t.Errorf("rotateBackups = %v, %v", removed, err)
+	}
+	if _, err := rotateBackups(dir, "tasks", -1); err == nil {
+		t.Error("expected an error for a negative keep")
+	}
+}
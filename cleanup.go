@@ -0,0 +1,201 @@
+package main
+
+import (
+	"slices"
+	"strings"
+)
+
+// dedupe объединяет задачи с одинаковым текстом (без учета регистра)
+// Остаётся самая старая по дате создания задача, остальные удаляются
+// Если хотя бы один дубликат выполнен, оставшаяся задача тоже считается выполненной,
+// теги, заметки и зависимости удалённых дубликатов переносятся на оставшуюся задачу
+// Зависимости других задач от удалённых дубликатов переводятся на оставшуюся задачу
+// Выполненные прошлые повторения не считаются дубликатами следующих
+// Защищённые задачи без force не удаляются и не меняются: они пропускаются, а дубликаты объединяются без них
+// Возвращает количество удалённых дубликатов и ID пропущенных защищённых задач
+func dedupe(tl *TodoList, force bool) (int, []int) {
+	groups := make(map[string][]int)
+	var keys []string
+	for i, task := range tl.Tasks {
+		if pastOccurrence(task) {
+			continue
+		}
+
+		key := strings.ToLower(task.Content)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	remove := make(map[int]bool)
+	survivorIds := make(map[int]int, len(tl.Tasks))
+	for _, task := range tl.Tasks {
+		survivorIds[task.Id] = task.Id
+	}
+	var skipped []int
+	for _, key := range keys {
+		indexes := groups[key]
+		if len(indexes) < 2 {
+			continue
+		}
+
+		indexes = slices.DeleteFunc(indexes, func(i int) bool {
+			if protected(tl.Tasks[i], force) {
+				skipped = append(skipped, tl.Tasks[i].Id)
+				return true
+			}
+			return false
+		})
+		if len(indexes) < 2 {
+			continue
+		}
+
+		survivor := indexes[0]
+		for _, i := range indexes[1:] {
+			if createdBefore(tl.Tasks[i], tl.Tasks[survivor]) {
+				survivor = i
+			}
+		}
+
+		for _, i := range indexes {
+			if i == survivor {
+				continue
+			}
+
+			mergeDuplicate(&tl.Tasks[survivor], tl.Tasks[i])
+			survivorIds[tl.Tasks[i].Id] = tl.Tasks[survivor].Id
+			remove[i] = true
+		}
+	}
+
+	if len(remove) == 0 {
+		return 0, skipped
+	}
+
+	kept := make([]Task, 0, len(tl.Tasks)-len(remove))
+	for i, task := range tl.Tasks {
+		if !remove[i] {
+			kept = append(kept, task)
+		}
+	}
+
+	remapBlockers(kept, survivorIds)
+	tl.Tasks = kept
+	return len(remove), skipped
+}
+
+// createdBefore проверяет, создана ли задача a раньше задачи b
+// Задачи с некорректной датой создания считаются более новыми
+func createdBefore(a, b Task) bool {
+	ta, errA := parseTime(a.CreatedAt)
+	tb, errB := parseTime(b.CreatedAt)
+	if errA != nil {
+		return false
+	}
+	if errB != nil {
+		return true
+	}
+
+	return ta.Before(tb)
+}
+
+// mergeDuplicate переносит на оставшуюся задачу статус выполнения, теги, заметки и зависимости удаляемого дубликата
+// Строки заметок дубликата дописываются с новой строки, если их ещё нет в заметках оставшейся задачи
+func mergeDuplicate(survivor *Task, dup Task) {
+	mergeDone(survivor, dup)
+	for _, tag := range dup.Tags {
+		survivor.Tags = appendTag(survivor.Tags, tag)
+	}
+	for line := range strings.Lines(dup.Notes) {
+		line = strings.TrimRight(line, "\n")
+		if line == "" || slices.Contains(strings.Split(survivor.Notes, "\n"), line) {
+			continue
+		}
+		if survivor.Notes != "" {
+			survivor.Notes += "\n"
+		}
+		survivor.Notes += line
+	}
+	survivor.BlockedBy = append(survivor.BlockedBy, dup.BlockedBy...)
+}
+
+// mergeDone переносит статус выполнения дубликата на оставшуюся задачу
+// Из нескольких выполненных дубликатов сохраняется самое раннее время завершения
+func mergeDone(survivor *Task, dup Task) {
+	if !dup.Done {
+		return
+	}
+
+	if !survivor.Done || earlierTime(dup.CompletedAt, survivor.CompletedAt) {
+		survivor.Done = true
+		survivor.CompletedAt = dup.CompletedAt
+	}
+}
+
+// earlierTime проверяет, что момент a корректен и раньше момента b
+func earlierTime(a, b string) bool {
+	ta, err := parseTime(a)
+	if err != nil {
+		return false
+	}
+
+	tb, err := parseTime(b)
+	return err != nil || ta.Before(tb)
+}
+
+// normalizeContent убирает пробелы по краям и заменяет последовательности пробельных символов одним пробелом
+// Повторное применение не меняет результат
+func normalizeContent(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// levenshtein вычисляет расстояние Левенштейна между строками по символам
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// nearDuplicates находит пары задач, тексты которых отличаются не более чем на maxDist правок
+// Сравнение выполняется без учета регистра, задача не сравнивается сама с собой,
+// прошлые повторения повторяющихся задач пропускаются. Возвращает пары ID в порядке следования задач в списке
+func nearDuplicates(tl *TodoList, maxDist int) [][2]int {
+	var pairs [][2]int
+	for i := range tl.Tasks {
+		if pastOccurrence(tl.Tasks[i]) {
+			continue
+		}
+
+		a := strings.ToLower(tl.Tasks[i].Content)
+		for j := i + 1; j < len(tl.Tasks); j++ {
+			if pastOccurrence(tl.Tasks[j]) {
+				continue
+			}
+
+			b := strings.ToLower(tl.Tasks[j].Content)
+			if levenshtein(a, b) <= maxDist {
+				pairs = append(pairs, [2]int{tl.Tasks[i].Id, tl.Tasks[j].Id})
+			}
+		}
+	}
+
+	return pairs
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDedupe(t *testing.T) {
+	tl := &TodoList{NextId: 6, Tasks: []Task{
+		{Id: 1, Content: "Buy milk", CreatedAt: "2024-05-02 10:00:00"},
+		{Id: 2, Content: "buy milk", CreatedAt: "2024-05-01 10:00:00"},
+		{Id: 3, Content: "BUY MILK", CreatedAt: "2024-05-03 10:00:00", Done: true, CompletedAt: "2024-05-04 10:00:00"},
+		{Id: 4, Content: "Call mom", CreatedAt: "2024-05-01 10:00:00"},
+		{Id: 5, Content: "Buy milk ", CreatedAt: "2024-05-01 09:00:00"},
+	}}
+
+	if merged, _ := dedupe(tl, false); merged != 2 {
+		t.Errorf("dedupe = %d, want 2", merged)
+	}
+
+	if got, want := taskIds(tl.Tasks), []int{2, 4, 5}; !slices.Equal(got, want) {
+		t.Fatalf("remaining = %v, want %v", got, want)
+	}
+
+	survivor := tl.Tasks[0]
+	if !survivor.Done || survivor.CompletedAt != "2024-05-04 10:00:00" {
+		t.Errorf("survivor done = %v, completed at %q; want the duplicate's completion", survivor.Done, survivor.CompletedAt)
+	}
+	if tl.Tasks[1].Done {
+		t.Error("unrelated task became done")
+	}
+}
+
+func TestDedupeKeepsEarliestCompletion(t *testing.T) {
+	tl := &TodoList{Tasks: []Task{
+		{Id: 1, Content: "a", CreatedAt: "2024-05-01 10:00:00", Done: true, CompletedAt: "2024-05-05 10:00:00"},
+		{Id: 2, Content: "A", CreatedAt: "2024-05-02 10:00:00", Done: true, CompletedAt: "2024-05-03 10:00:00"},
+	}}
+
+	if merged, _ := dedupe(tl, false); merged != 1 {
+		t.Fatalf("dedupe = %d, want 1", merged)
+	}
+	if got := tl.Tasks[0]; got.Id != 1 || got.CompletedAt != "2024-05-03 10:00:00" {
+		t.Errorf("survivor = #%d completed at %q", got.Id, got.CompletedAt)
+	}
+}
+
+func TestDedupeNoDuplicates(t *testing.T) {
+	tl := newTestList("a", "b")
+	if merged, _ := dedupe(tl, false); merged != 0 || len(tl.Tasks) != 2 {
+		t.Errorf("dedupe = %d, tasks = %v", merged, tl.Tasks)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"milk", "", 4},
+		{"milk", "milks", 1},
+		{"kitten", "sitting", 3},
+		{"молоко", "малако", 2},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestNearDuplicates(t *testing.T) {
+	tl := newTestList("Buy milk", "buy milks", "Call mom", "Fix the roof", "Buy mlk")
+
+	got := nearDuplicates(tl, 2)
+	want := [][2]int{{1, 2}, {1, 5}, {2, 5}}
+	if !slices.Equal(got, want) {
+		t.Errorf("nearDuplicates = %v, want %v", got, want)
+	}
+
+	if got := nearDuplicates(tl, 0); len(got) != 0 {
+		t.Errorf("nearDuplicates(maxDist 0) = %v, want none", got)
+	}
+}
+
+func TestNormalizeContent(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Buy milk", "Buy milk"},
+		{"  Buy milk  ", "Buy milk"},
+		{"Buy   milk", "Buy milk"},
+		{"Buy\t\tmilk\n", "Buy milk"},
+		{"\t Купить \t молоко ", "Купить молоко"},
+		{"   ", ""},
+	}
+
+	for _, tt := range tests {
+		got := normalizeContent(tt.in)
+		if got != tt.want {
+			t.Errorf("normalizeContent(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if again := normalizeContent(got); again != got {
+			t.Errorf("normalizeContent is not idempotent: %q -> %q", got, again)
+		}
+	}
+}
+
+func TestNormalizeAllTasks(t *testing.T) {
+	tl := newTestList("clean", "  two  spaces ", "\t", "tab\there")
+	all := func(Task) bool { return true }
+
+	changed, skipped := applyTransform(tl, all, normalizeContent, testNow)
+
+	if changed != 2 || !slices.Equal(skipped, []int{3}) {
+		t.Errorf("applyTransform = %d, %v; want 2, [3]", changed, skipped)
+	}
+	if got := tl.Tasks[1].Content; got != "two spaces" {
+		t.Errorf("task #2 = %q", got)
+	}
+	if got := tl.Tasks[2].Content; got != "\t" {
+		t.Errorf("skipped task changed to %q", got)
+	}
+}
+
+func TestDedupeMergesTags(t *testing.T) {
+	tl := &TodoList{Tasks: []Task{
+		{Id: 1, Content: "a", CreatedAt: "2024-05-01 10:00:00", Tags: []string{"work"}},
+		{Id: 2, Content: "A", CreatedAt: "2024-05-02 10:00:00", Tags: []string{"Work", "home"}},
+	}}
+
+	if merged, _ := dedupe(tl, false); merged != 1 {
+		t.Fatalf("dedupe = %d, want 1", merged)
+	}
+	if got, want := tl.Tasks[0].Tags, []string{"work", "home"}; !slices.Equal(got, want) {
+		t.Errorf("survivor tags = %q, want %q", got, want)
+	}
+}
+
+func TestDedupeMergesNotes(t *testing.T) {
+	tl := &TodoList{Tasks: []Task{
+		{Id: 1, Content: "a", CreatedAt: "2024-05-01 10:00:00", Notes: "первая"},
+		{Id: 2, Content: "a", CreatedAt: "2024-05-02 10:00:00", Notes: "вторая"},
+		{Id: 3, Content: "a", CreatedAt: "2024-05-03 10:00:00", Notes: "первая"},
+		{Id: 4, Content: "b", CreatedAt: "2024-05-01 10:00:00"},
+		{Id: 5, Content: "b", CreatedAt: "2024-05-02 10:00:00", Notes: "только у дубликата"},
+	}}
+
+	dedupe(tl, false)
+	if got, want := tl.Tasks[0].Notes, "первая\nвторая"; got != want {
+		t.Errorf("notes of #1 = %q, want %q", got, want)
+	}
+	if got, want := tl.Tasks[1].Notes, "только у дубликата"; got != want {
+		t.Errorf("notes of #4 = %q, want %q", got, want)
+	}
+}
+
+func TestDedupeRemapsBlockers(t *testing.T) {
+	tl := &TodoList{Tasks: []Task{
+		{Id: 1, Content: "a", CreatedAt: "2024-05-01 10:00:00"},
+		{Id: 2, Content: "a", CreatedAt: "2024-05-02 10:00:00", BlockedBy: []int{4}},
+		{Id: 3, Content: "c", CreatedAt: "2024-05-01 10:00:00", BlockedBy: []int{2, 1}},
+		{Id: 4, Content: "d", CreatedAt: "2024-05-01 10:00:00"},
+		{Id: 5, Content: "e", CreatedAt: "2024-05-01 10:00:00", BlockedBy: []int{1}},
+		{Id: 6, Content: "E", CreatedAt: "2024-05-02 10:00:00", BlockedBy: []int{2}},
+	}}
+
+	dedupe(tl, false)
+	want := map[int][]int{1: {4}, 3: {1}, 4: nil, 5: {1}}
+	if got := taskIds(tl.Tasks); !slices.Equal(got, []int{1, 3, 4, 5}) {
+		t.Fatalf("tasks after dedupe = %v, want [1 3 4 5]", got)
+	}
+	for _, task := range tl.Tasks {
+		if !slices.Equal(task.BlockedBy, want[task.Id]) {
+			t.Errorf("blockers of #%d = %v, want %v", task.Id, task.BlockedBy, want[task.Id])
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// colorCodes сопоставляет допустимые цветовые метки с кодами ANSI
+var colorCodes = map[string]string{
+	"red":     "31",
+	"green":   "32",
+	"yellow":  "33",
+	"blue":    "34",
+	"magenta": "35",
+	"cyan":    "36",
+}
+
+// normalizeColor приводит цветовую метку к нижнему регистру и проверяет, что она известна
+// Пустое значение и none означают отсутствие метки
+func normalizeColor(color string) (string, error) {
+	color = strings.ToLower(strings.TrimSpace(color))
+	if color == "" || color == "none" {
+		return "", nil
+	}
+
+	if _, ok := colorCodes[color]; !ok {
+		return "", fmt.Errorf(msg("Ошибка: неизвестный цвет %q (допустимы red, green, yellow, blue, magenta, cyan)"), color)
+	}
+
+	return color, nil
+}
+
+// colorMarker возвращает метку цвета задачи для вывода в списке
+// При enabled метка выводится цветным символом, иначе — названием цвета в скобках
+// Для задачи без цвета возвращается пустая строка
+func colorMarker(color string, enabled bool) string {
+	code, ok := colorCodes[color]
+	if !ok {
+		return ""
+	}
+
+	if enabled {
+		return "\x1b[" + code + "m●\x1b[0m "
+	}
+
+	return "(" + color + ") "
+}
+
+// setColor устанавливает или снимает цветовую метку задачи
+func setColor(tl *TodoList, id int, color string) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	color, err := normalizeColor(color)
+	if err != nil {
+		return err
+	}
+
+	tl.Tasks[index].Color = color
+	return nil
+}
+
+// isTerminal проверяет, что файл является терминалом
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestNormalizeColor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"red", "red", false},
+		{" Blue ", "blue", false},
+		{"", "", false},
+		{"none", "", false},
+		{"purple", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := normalizeColor(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("normalizeColor(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestColorMarker(t *testing.T) {
+	tests := []struct {
+		color   string
+		enabled bool
+		want    string
+	}{
+		{"red", true, "\x1b[31m●\x1b[0m "},
+		{"red", false, "(red) "},
+		{"", true, ""},
+		{"", false, ""},
+		{"purple", true, ""},
+	}
+
+	for _, tt := range tests {
+		if got := colorMarker(tt.color, tt.enabled); got != tt.want {
+			t.Errorf("colorMarker(%q, %v) = %q, want %q", tt.color, tt.enabled, got, tt.want)
+		}
+	}
+}
+
+func TestSetColor(t *testing.T) {
+	tl := newTestList("a")
+	if err := setColor(tl, 1, "Green"); err != nil || tl.Tasks[0].Color != "green" {
+		t.Fatalf("setColor = %v, color %q", err, tl.Tasks[0].Color)
+	}
+	if err := setColor(tl, 1, "purple"); err == nil || tl.Tasks[0].Color != "green" {
+		t.Errorf("invalid color: error %v, color %q", err, tl.Tasks[0].Color)
+	}
+	if err := setColor(tl, 1, "none"); err != nil || tl.Tasks[0].Color != "" {
+		t.Errorf("clear: error %v, color %q", err, tl.Tasks[0].Color)
+	}
+}
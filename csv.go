@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvHeader — заголовок CSV-экспорта, столбцы соответствуют полям Task
+// Подзадачи, история и заметки не выгружаются
+var csvHeader = []string{"id", "content", "done", "created_at", "completed_at", "tags", "due_date", "created_by", "assignee", "priority", "estimate", "blocked_by"}
+
+// exportCSV записывает задачи в формате CSV с заголовком
+// Теги и зависимости сохраняются строкой через запятую
+func exportCSV(tl *TodoList, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, task := range tl.Tasks {
+		estimate := ""
+		if task.Estimate > 0 {
+			estimate = strconv.FormatFloat(task.Estimate, 'g', -1, 64)
+		}
+
+		record := []string{
+			strconv.Itoa(task.Id),
+			task.Content,
+			strconv.FormatBool(task.Done),
+			task.CreatedAt,
+			task.CompletedAt,
+			strings.Join(task.Tags, ","),
+			task.DueDate,
+			task.CreatedBy,
+			task.Assignee,
+			task.Priority,
+			estimate,
+			joinIds(task.BlockedBy),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
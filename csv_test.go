@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"testing"
+)
+
+func TestExportCSVFiltered(t *testing.T) {
+	tl := newTestList("buy milk", "call, mom", "read book", "buy bread")
+	tl.Tasks[0].Tags = []string{"shop", "home"}
+	tl.Tasks[1].Tags = []string{"shop"}
+	tl.Tasks[1].Priority = priorityHigh
+	tl.Tasks[1].Estimate = 1.5
+	tl.Tasks[1].BlockedBy = []int{1, 3}
+	markDone(&tl.Tasks[3], testNow)
+
+	tests := []struct {
+		name string
+		opts FilterOptions
+		want []string
+	}{
+		{"tag", FilterOptions{Tag: "SHOP"}, []string{"1", "2"}},
+		{"status", FilterOptions{Status: statusDone}, []string{"4"}},
+		{"priority", FilterOptions{Priority: priorityHigh}, []string{"2"}},
+		{"search", FilterOptions{Search: "buy", Status: statusPending}, []string{"1"}},
+		{"nothing matches", FilterOptions{Tag: "work"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := exportCSV(&TodoList{Tasks: applyFilters(tl, tt.opts)}, &buf); err != nil {
+				t.Fatal(err)
+			}
+
+			records, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(records[0], csvHeader) {
+				t.Errorf("header = %q", records[0])
+			}
+			var ids []string
+			for _, record := range records[1:] {
+				ids = append(ids, record[0])
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("exported ids = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportCSVFields(t *testing.T) {
+	tl := newTestList("call, mom")
+	tl.Tasks[0].Tags = []string{"shop", "home"}
+	tl.Tasks[0].Priority = priorityHigh
+	tl.Tasks[0].Estimate = 1.5
+	tl.Tasks[0].BlockedBy = []int{1, 3}
+
+	var buf bytes.Buffer
+	if err := exportCSV(tl, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(records) != 2 {
+		t.Fatalf("records = %q, %v", records, err)
+	}
+	want := []string{"1", "call, mom", "false", tl.Tasks[0].CreatedAt, "", "shop,home", "", "", "", priorityHigh, "1.5", "1,3"}
+	if !slices.Equal(records[1], want) {
+		t.Errorf("record = %q, want %q", records[1], want)
+	}
+}
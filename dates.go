@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02" // Формат хранения сроков выполнения
+
+// parseTime разбирает дату и время в формате хранения задач в локальной зоне
+func parseTime(s string) (time.Time, error) {
+	return time.ParseInLocation(timeLayout, s, time.Local)
+}
+
+// formatTime форматирует время для вывода по макету Go (пустой макет — формат хранения)
+func formatTime(t time.Time, layout string) string {
+	if layout == "" {
+		layout = timeLayout
+	}
+	return t.Format(layout)
+}
+
+// displayTime переводит сохранённое время в формат вывода
+// Если значение не удаётся разобрать, оно выводится как есть
+func displayTime(s, layout string) string {
+	if layout == "" {
+		return s
+	}
+
+	t, err := parseTime(s)
+	if err != nil {
+		return s
+	}
+	return formatTime(t, layout)
+}
+
+// validateTimeLayout проверяет, что макет содержит хотя бы один элемент даты или времени
+// Go не сообщает об ошибках в макетах, поэтому макет без элементов выводился бы как есть
+func validateTimeLayout(layout string) error {
+	if layout == "" {
+		return nil
+	}
+
+	ref := time.Date(2001, time.February, 3, 4, 5, 6, 0, time.UTC)
+	if ref.Format(layout) == layout {
+		return fmt.Errorf(msg("Ошибка: не верный формат времени %q (используйте макет Go, например \"02.01.2006 15:04\")"), layout)
+	}
+	return nil
+}
+
+// humanizeAge возвращает возраст в удобном для чтения виде, например "3 дн."
+func humanizeAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return msg("меньше минуты")
+	case d < time.Hour:
+		return fmt.Sprintf(msg("%d мин."), int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf(msg("%d ч."), int(d/time.Hour))
+	default:
+		return fmt.Sprintf(msg("%d дн."), int(d/(24*time.Hour)))
+	}
+}
+
+// parseDate разбирает абсолютную (2006-01-02) или относительную дату
+// Относительные даты: today/сегодня, tomorrow/завтра, +Nd (дни), +Nw (недели)
+func parseDate(s string, now time.Time) (time.Time, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch s {
+	case "today", "сегодня":
+		return today, nil
+	case "tomorrow", "завтра":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	if strings.HasPrefix(s, "+") && len(s) > 2 {
+		n, err := strconv.Atoi(s[1 : len(s)-1])
+		if err == nil && n >= 0 {
+			switch s[len(s)-1] {
+			case 'd':
+				return today.AddDate(0, 0, n), nil
+			case 'w':
+				return today.AddDate(0, 0, 7*n), nil
+			}
+		}
+	}
+
+	t, err := time.ParseInLocation(dateLayout, s, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf(msg("Ошибка: не верная дата %q"), s)
+	}
+
+	return t, nil
+}
+
+// extractDueToken ищет в тексте задачи срок в виде слова due:2024-06-01 или @2024-06-01
+// (допустимы и значения вроде today или +3d) и возвращает текст без этого слова и срок
+// Слова с некорректной датой остаются частью текста. Учитывается первое подходящее слово
+func extractDueToken(content string, now time.Time) (string, time.Time, bool) {
+	words := strings.Fields(content)
+	for i, word := range words {
+		value, ok := strings.CutPrefix(word, "due:")
+		if !ok {
+			value, ok = strings.CutPrefix(word, "@")
+		}
+		if !ok || value == "" {
+			continue
+		}
+
+		due, err := parseDate(value, now)
+		if err != nil {
+			continue
+		}
+
+		cleaned := append(append([]string(nil), words[:i]...), words[i+1:]...)
+		return strings.Join(cleaned, " "), due, true
+	}
+
+	return content, time.Time{}, false
+}
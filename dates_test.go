@@ -0,0 +1,119 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHumanizeAge(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Second, "меньше минуты"},
+		{5 * time.Minute, "5 мин."},
+		{3 * time.Hour, "3 ч."},
+		{50 * time.Hour, "2 дн."},
+	}
+
+	for _, tt := range tests {
+		if got := humanizeAge(tt.d); got != tt.want {
+			t.Errorf("humanizeAge(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	at := time.Date(2024, 6, 1, 9, 5, 3, 0, time.Local)
+
+	tests := []struct {
+		layout string
+		want   string
+	}{
+		{"", "2024-06-01 09:05:03"},
+		{"02.01.2006 15:04", "01.06.2024 09:05"},
+		{"Jan 2, 3:04PM", "Jun 1, 9:05AM"},
+		{time.DateOnly, "2024-06-01"},
+	}
+
+	for _, tt := range tests {
+		if got := formatTime(at, tt.layout); got != tt.want {
+			t.Errorf("formatTime(%q) = %q, want %q", tt.layout, got, tt.want)
+		}
+	}
+}
+
+func TestDisplayTime(t *testing.T) {
+	if got := displayTime("2024-06-01 09:05:03", "02.01.2006"); got != "01.06.2024" {
+		t.Errorf("displayTime = %q", got)
+	}
+	if got := displayTime("2024-06-01 09:05:03", ""); got != "2024-06-01 09:05:03" {
+		t.Errorf("displayTime without layout = %q", got)
+	}
+	if got := displayTime("broken", "02.01.2006"); got != "broken" {
+		t.Errorf("displayTime for an invalid value = %q", got)
+	}
+}
+
+func TestValidateTimeLayout(t *testing.T) {
+	for _, layout := range []string{"", "02.01.2006 15:04", time.RFC3339} {
+		if err := validateTimeLayout(layout); err != nil {
+			t.Errorf("validateTimeLayout(%q) = %v", layout, err)
+		}
+	}
+	for _, layout := range []string{"dd.mm.yyyy", "%Y-%m-%d"} {
+		if err := validateTimeLayout(layout); err == nil {
+			t.Errorf("validateTimeLayout(%q): expected an error", layout)
+		}
+	}
+}
+
+func TestExtractDueToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantDue string
+		wantOk  bool
+	}{
+		{"due prefix", "Pay rent due:2024-06-05", "Pay rent", "2024-06-05", true},
+		{"at prefix", "@2024-06-05 Pay rent", "Pay rent", "2024-06-05", true},
+		{"relative", "Call mom @tomorrow", "Call mom", "2024-06-02", true},
+		{"first token wins", "a due:+3d @2024-07-01", "a @2024-07-01", "2024-06-04", true},
+		{"malformed stays literal", "Meet @bob due:someday", "Meet @bob due:someday", "", false},
+		{"no token", "Buy milk", "Buy milk", "", false},
+		{"bare marker", "Email @ noon", "Email @ noon", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, due, ok := extractDueToken(tt.content, testNow)
+			if got != tt.want || ok != tt.wantOk {
+				t.Fatalf("extractDueToken = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOk)
+			}
+			if ok && due.Format(dateLayout) != tt.wantDue {
+				t.Errorf("due = %s, want %s", due.Format(dateLayout), tt.wantDue)
+			}
+		})
+	}
+}
+
+func TestCreateTaskInlineDue(t *testing.T) {
+	tl := newTestList()
+	task, err := createTask(tl, "Pay rent @2024-06-05", AddOptions{}, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Content != "Pay rent" || task.DueDate != "2024-06-05" {
+		t.Errorf("task = %q, due %q", task.Content, task.DueDate)
+	}
+
+	// Явно указанный срок важнее срока из текста
+	task, err = createTask(tl, "Pay tax @2024-06-05", AddOptions{DueDate: "2024-07-01"}, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Content != "Pay tax" || task.DueDate != "2024-07-01" {
+		t.Errorf("task = %q, due %q", task.Content, task.DueDate)
+	}
+}
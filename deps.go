@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// addBlocker добавляет задаче зависимость от другой задачи
+func addBlocker(tl *TodoList, id, blocker int) error {
+	if id == blocker {
+		return errors.New(msg("Ошибка: задача не может зависеть от самой себя"))
+	}
+
+	index := findTaskIndex(tl, id)
+	if index == -1 || findTaskIndex(tl, blocker) == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	task := &tl.Tasks[index]
+	if !slices.Contains(task.BlockedBy, blocker) {
+		task.BlockedBy = append(task.BlockedBy, blocker)
+	}
+	return nil
+}
+
+// removeBlocker удаляет зависимость задачи от другой задачи
+func removeBlocker(tl *TodoList, id, blocker int) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	task := &tl.Tasks[index]
+	task.BlockedBy = slices.DeleteFunc(task.BlockedBy, func(b int) bool { return b == blocker })
+	return nil
+}
+
+// openBlockers возвращает ID невыполненных задач, от которых зависит задача
+// Ссылки на удалённые задачи не блокируют
+func openBlockers(tl *TodoList, task Task) []int {
+	var open []int
+	for _, blocker := range task.BlockedBy {
+		index := findTaskIndex(tl, blocker)
+		if index != -1 && !tl.Tasks[index].Done {
+			open = append(open, blocker)
+		}
+	}
+	return open
+}
+
+// remapBlockers заменяет ID в зависимостях задач по таблице newIds
+// Ссылки на задачи, которых нет в таблице, отбрасываются, как и ссылки задачи на саму себя
+// и повторы, появившиеся после слияния нескольких ID в один
+func remapBlockers(tasks []Task, newIds map[int]int) {
+	for i := range tasks {
+		var blockers []int
+		for _, b := range tasks[i].BlockedBy {
+			if id, ok := newIds[b]; ok && id != tasks[i].Id && !slices.Contains(blockers, id) {
+				blockers = append(blockers, id)
+			}
+		}
+		tasks[i].BlockedBy = blockers
+	}
+}
+
+// isBlocked сообщает, есть ли у задачи невыполненные зависимости
+func isBlocked(tl *TodoList, task Task) bool {
+	return len(openBlockers(tl, task)) > 0
+}
+
+// blockedTasks возвращает невыполненные задачи, ожидающие других задач
+func blockedTasks(tl *TodoList) []Task {
+	var tasks []Task
+	for _, task := range tl.Tasks {
+		if !task.Done && isBlocked(tl, task) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// formatIds форматирует список ID как "#1, #2"
+func formatIds(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = "#" + strconv.Itoa(id)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// listBlocked выводит заблокированные задачи вместе с блокирующими их ID
+func listBlocked(tl *TodoList) {
+	tasks := blockedTasks(tl)
+	if len(tasks) == 0 {
+		fmt.Println(msg("Нет заблокированных задач"))
+		return
+	}
+
+	fmt.Println(msg("Заблокированные задачи:"))
+	for _, task := range tasks {
+		fmt.Printf(msg("%d, %s (ждёт: %s)\n"), task.Id, task.Content, formatIds(openBlockers(tl, task)))
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBlockedTasksChain(t *testing.T) {
+	// 3 ждёт 2, 2 ждёт 1
+	tl := newTestList("design", "build", "ship")
+	tl.Tasks[1].BlockedBy = []int{1}
+	tl.Tasks[2].BlockedBy = []int{2}
+
+	if got := taskIds(blockedTasks(tl)); !slices.Equal(got, []int{2, 3}) {
+		t.Errorf("blocked = %v, want [2 3]", got)
+	}
+
+	markDone(&tl.Tasks[0], testNow)
+	if got := taskIds(blockedTasks(tl)); !slices.Equal(got, []int{3}) {
+		t.Errorf("after completing #1 blocked = %v, want [3]", got)
+	}
+
+	markDone(&tl.Tasks[1], testNow)
+	if got := blockedTasks(tl); len(got) != 0 {
+		t.Errorf("after completing #2 blocked = %v, want none", taskIds(got))
+	}
+}
+
+func TestBlockedTasksDeletedBlocker(t *testing.T) {
+	tl := newTestList("a")
+	tl.Tasks[0].BlockedBy = []int{7}
+
+	if got := blockedTasks(tl); len(got) != 0 {
+		t.Errorf("blocked = %v, want none", taskIds(got))
+	}
+}
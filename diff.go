@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// matchTasks сопоставляет задачи двух списков: сначала по тексту без учета регистра,
+// затем оставшиеся — по ID. Возвращает соответствие индексов задач a индексам задач b
+func matchTasks(a, b *TodoList) map[int]int {
+	pairs := make(map[int]int)
+	used := make(map[int]bool)
+
+	byContent := make(map[string][]int)
+	for j, task := range b.Tasks {
+		key := strings.ToLower(task.Content)
+		byContent[key] = append(byContent[key], j)
+	}
+	for i, task := range a.Tasks {
+		key := strings.ToLower(task.Content)
+		if candidates := byContent[key]; len(candidates) > 0 {
+			pairs[i] = candidates[0]
+			used[candidates[0]] = true
+			byContent[key] = candidates[1:]
+		}
+	}
+
+	for i, task := range a.Tasks {
+		if _, ok := pairs[i]; ok {
+			continue
+		}
+		for j := range b.Tasks {
+			if !used[j] && b.Tasks[j].Id == task.Id {
+				pairs[i] = j
+				used[j] = true
+				break
+			}
+		}
+	}
+
+	return pairs
+}
+
+// diffLists сравнивает два списка задач
+// Возвращает задачи только из a, только из b и задачи из a, у которых в b отличается текст или статус
+func diffLists(a, b *TodoList) (onlyA, onlyB, changed []Task) {
+	pairs := matchTasks(a, b)
+	matched := make(map[int]bool)
+	for i, task := range a.Tasks {
+		j, ok := pairs[i]
+		if !ok {
+			onlyA = append(onlyA, task)
+			continue
+		}
+
+		matched[j] = true
+		if other := b.Tasks[j]; other.Content != task.Content || other.Done != task.Done {
+			changed = append(changed, task)
+		}
+	}
+
+	for j, task := range b.Tasks {
+		if !matched[j] {
+			onlyB = append(onlyB, task)
+		}
+	}
+
+	return onlyA, onlyB, changed
+}
+
+// printDiff выводит различия между текущим списком и списком из файла name
+func printDiff(a, b *TodoList, name string, w io.Writer) {
+	onlyA, onlyB, changed := diffLists(a, b)
+	if len(onlyA) == 0 && len(onlyB) == 0 && len(changed) == 0 {
+		fmt.Fprintln(w, msg("Списки совпадают"))
+		return
+	}
+
+	status := func(task Task) string {
+		if task.Done {
+			return "x"
+		}
+		return " "
+	}
+
+	if len(onlyA) > 0 {
+		fmt.Fprintln(w, msg("Только в текущем списке:"))
+		for _, task := range onlyA {
+			fmt.Fprintf(w, "- %d [%s], %s\n", task.Id, status(task), task.Content)
+		}
+	}
+	if len(onlyB) > 0 {
+		fmt.Fprintf(w, msg("Только в %s:\n"), name)
+		for _, task := range onlyB {
+			fmt.Fprintf(w, "+ %d [%s], %s\n", task.Id, status(task), task.Content)
+		}
+	}
+	if len(changed) > 0 {
+		pairs := matchTasks(a, b)
+		fmt.Fprintln(w, msg("Изменённые задачи:"))
+		for i, task := range a.Tasks {
+			j, ok := pairs[i]
+			if !ok || b.Tasks[j].Content == task.Content && b.Tasks[j].Done == task.Done {
+				continue
+			}
+			other := b.Tasks[j]
+			fmt.Fprintf(w, "~ %d [%s], %s -> %d [%s], %s\n", task.Id, status(task), task.Content, other.Id, status(other), other.Content)
+		}
+	}
+}
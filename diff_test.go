@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDiffLists(t *testing.T) {
+	a := &TodoList{Tasks: []Task{
+		{Id: 1, Content: "Buy milk"},
+		{Id: 2, Content: "Call mom"},
+		{Id: 3, Content: "Fix roof"},
+		{Id: 4, Content: "Only here"},
+		{Id: 5, Content: "Old title"},
+	}}
+	b := &TodoList{Tasks: []Task{
+		{Id: 7, Content: "buy MILK"},             // Тот же текст без учета регистра, другой ID
+		{Id: 8, Content: "Call mom", Done: true}, // Изменился статус
+		{Id: 5, Content: "New title"},            // Совпадение по ID, изменился текст
+		{Id: 9, Content: "Only there"},
+		{Id: 3, Content: "Fix roof"},
+	}}
+
+	onlyA, onlyB, changed := diffLists(a, b)
+
+	if got := taskIds(onlyA); !slices.Equal(got, []int{4}) {
+		t.Errorf("onlyA = %v, want [4]", got)
+	}
+	if got := taskIds(onlyB); !slices.Equal(got, []int{9}) {
+		t.Errorf("onlyB = %v, want [9]", got)
+	}
+	if got := taskIds(changed); !slices.Equal(got, []int{1, 2, 5}) {
+		t.Errorf("changed = %v, want [1 2 5]", got)
+	}
+}
+
+func TestPrintDiff(t *testing.T) {
+	tl := newTestList("a", "b")
+
+	var buf bytes.Buffer
+	printDiff(tl, newTestList("A", "b"), "other.json", &buf)
+	if !strings.Contains(buf.String(), "~ 1 [ ], a -> 1 [ ], A") {
+		t.Errorf("case-only difference not reported:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	printDiff(tl, newTestList("a", "b"), "other.json", &buf)
+	if strings.TrimSpace(buf.String()) != "Списки совпадают" {
+		t.Errorf("identical lists output:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// readFromEditor открывает редактор на временном файле с начальным текстом и возвращает сохранённый текст
+// Команда редактора может содержать аргументы, например "code -w". Если редактор не задан,
+// текст читается из stdin. Временный файл удаляется после чтения
+func readFromEditor(editor, initial string, stdin io.Reader) (string, error) {
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		data, err := io.ReadAll(stdin)
+		return string(data), err
+	}
+
+	tmp, err := os.CreateTemp("", "todo-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(initial); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command(args[0], append(args[1:], tmp.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(tmp.Name())
+	return string(data), err
+}
+
+// splitComposed делит набранный текст на текст задачи (первая непустая строка) и заметки (остальные строки)
+func splitComposed(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	content, notes, _ := strings.Cut(text, "\n")
+	return strings.TrimSpace(content), strings.TrimSpace(notes)
+}
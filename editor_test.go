@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeEditor создаёт скрипт редактора, который сохраняет путь и содержимое открытого файла в журнал
+// и заменяет содержимое файла текстом text
+func fakeEditor(t *testing.T, text string) (command, logPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	logPath = filepath.Join(dir, "editor.log")
+	command = filepath.Join(dir, "editor.sh")
+	script := "#!/bin/sh\necho \"$1\" > " + logPath + "\ncat \"$1\" >> " + logPath + "\nprintf '" + text + "' > \"$1\"\n"
+	if err := os.WriteFile(command, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return command, logPath
+}
+
+func TestReadFromEditor(t *testing.T) {
+	command, logPath := fakeEditor(t, "Купить молоко\\n\\n2 литра\\n")
+
+	text, err := readFromEditor(command, "черновик", strings.NewReader("stdin is ignored"))
+	if err != nil {
+		t.Fatalf("readFromEditor: %v", err)
+	}
+	if text != "Купить молоко\n\n2 литра\n" {
+		t.Errorf("text = %q", text)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("editor was not run: %v", err)
+	}
+	tmpPath, initial, _ := strings.Cut(string(data), "\n")
+	if initial != "черновик" {
+		t.Errorf("editor saw %q, want the initial text", initial)
+	}
+	if _, err := os.Stat(tmpPath); !os.IsNotExist(err) {
+		t.Errorf("temp file %s was not removed: %v", tmpPath, err)
+	}
+
+	if content, notes := splitComposed(text); content != "Купить молоко" || notes != "2 литра" {
+		t.Errorf("splitComposed = %q, %q", content, notes)
+	}
+}
+
+func TestReadFromEditorArgs(t *testing.T) {
+	command, logPath := fakeEditor(t, "text")
+
+	// Аргументы команды передаются редактору до имени файла
+	if _, err := readFromEditor("/bin/sh "+command, "", nil); err != nil {
+		t.Fatalf("readFromEditor: %v", err)
+	}
+	if _, err := os.Stat(logPath); err != nil {
+		t.Errorf("editor was not run: %v", err)
+	}
+}
+
+func TestReadFromEditorFallsBackToStdin(t *testing.T) {
+	text, err := readFromEditor("  ", "initial", strings.NewReader("from stdin\n"))
+	if err != nil || text != "from stdin\n" {
+		t.Errorf("readFromEditor = %q, %v", text, err)
+	}
+}
+
+func TestReadFromEditorFailure(t *testing.T) {
+	if _, err := readFromEditor("false", "", nil); err == nil {
+		t.Error("expected an error when the editor fails")
+	}
+}
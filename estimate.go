@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const velocityDays = 7 // Число последних дней, по которым считается скорость выполнения
+
+// parseEstimate разбирает оценку задачи в часах, пустая строка снимает оценку
+func parseEstimate(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	hours, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", "."), 64)
+	if err != nil || hours < 0 || math.IsInf(hours, 0) || math.IsNaN(hours) {
+		return 0, fmt.Errorf(msg("Ошибка: не верная оценка %q (ожидается число часов)"), s)
+	}
+	return hours, nil
+}
+
+// setEstimate устанавливает или снимает оценку задачи в часах
+func setEstimate(tl *TodoList, id int, estimate string) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	hours, err := parseEstimate(estimate)
+	if err != nil {
+		return err
+	}
+
+	tl.Tasks[index].Estimate = hours
+	return nil
+}
+
+// remainingEstimate возвращает сумму оценок невыполненных задач
+func remainingEstimate(tl *TodoList) float64 {
+	total := 0.0
+	for _, task := range tl.Tasks {
+		if !task.Done {
+			total += task.Estimate
+		}
+	}
+	return total
+}
+
+// completionVelocity возвращает среднее количество часов оценки, выполняемых за день
+// за последние velocityDays дней, включая сегодняшний
+func completionVelocity(tl *TodoList, now time.Time) float64 {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	since := today.AddDate(0, 0, -(velocityDays - 1))
+
+	done := 0.0
+	for _, task := range tl.Tasks {
+		if !task.Done {
+			continue
+		}
+
+		completedAt, err := parseTime(task.CompletedAt)
+		if err != nil || completedAt.Before(since) || completedAt.After(now) {
+			continue
+		}
+		done += task.Estimate
+	}
+
+	return done / velocityDays
+}
+
+// printEstimateRemaining выводит оставшийся объём работы и прогноз дней до завершения
+func printEstimateRemaining(tl *TodoList, now time.Time, w io.Writer) {
+	remaining := remainingEstimate(tl)
+	velocity := completionVelocity(tl, now)
+
+	fmt.Fprintf(w, msg("Осталось: %.1f ч.\n"), remaining)
+	fmt.Fprintf(w, msg("Скорость: %.1f ч. в день\n"), velocity)
+	switch {
+	case remaining == 0:
+		fmt.Fprintln(w, msg("До завершения: 0 дн."))
+	case velocity == 0:
+		fmt.Fprintln(w, msg("До завершения: неизвестно"))
+	default:
+		fmt.Fprintf(w, msg("До завершения: %.1f дн.\n"), remaining/velocity)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCompletionVelocity(t *testing.T) {
+	day := func(offset int) string {
+		return testNow.AddDate(0, 0, offset).Format(timeLayout)
+	}
+
+	tl := &TodoList{Tasks: []Task{
+		{Id: 1, Done: true, CompletedAt: day(0), Estimate: 4},
+		{Id: 2, Done: true, CompletedAt: day(-3), Estimate: 6},
+		{Id: 3, Done: true, CompletedAt: day(-6), Estimate: 4},
+		{Id: 4, Done: true, CompletedAt: day(-7), Estimate: 100},
+		{Id: 5, Estimate: 7},
+		{Id: 6, Estimate: 3.5},
+	}}
+
+	// За 7 дней выполнено 14 ч., задача #4 выполнена раньше
+	if got := completionVelocity(tl, testNow); got != 2 {
+		t.Errorf("completionVelocity = %v, want 2", got)
+	}
+	if got := remainingEstimate(tl); got != 10.5 {
+		t.Errorf("remainingEstimate = %v, want 10.5", got)
+	}
+
+	var buf bytes.Buffer
+	printEstimateRemaining(tl, testNow, &buf)
+	if !strings.Contains(buf.String(), "До завершения: 5.2 дн.") {
+		t.Errorf("forecast:\n%s", buf.String())
+	}
+}
+
+func TestEstimateRemainingZeroVelocity(t *testing.T) {
+	tl := &TodoList{Tasks: []Task{{Id: 1, Estimate: 3}}}
+
+	var buf bytes.Buffer
+	printEstimateRemaining(tl, testNow, &buf)
+	if !strings.Contains(buf.String(), "До завершения: неизвестно") {
+		t.Errorf("forecast:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// defaultTextTemplate — шаблон строки текстового экспорта по умолчанию
+const defaultTextTemplate = "{{.Id}}. [{{if .Done}}x{{else}} {{end}}] {{.Content}}"
+
+// encodeJSON сериализует значение в JSON
+// При compact вывод минимизирован, иначе форматируется с отступами
+func encodeJSON(v any, compact bool) ([]byte, error) {
+	if compact {
+		return json.Marshal(v)
+	}
+
+	return json.MarshalIndent(v, "", "  ")
+}
+
+// writeTasksJSON записывает задачи в виде JSON-массива
+// Пустой список записывается как []
+func writeTasksJSON(tasks []Task, w io.Writer, compact bool) error {
+	if tasks == nil {
+		tasks = []Task{}
+	}
+
+	data, err := encodeJSON(tasks, compact)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// taskFieldNames возвращает имена полей задачи в JSON в порядке объявления
+func taskFieldNames() []string {
+	t := reflect.TypeOf(Task{})
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// parseFields разбирает список полей через запятую и проверяет, что все поля существуют
+func parseFields(s string) ([]string, error) {
+	known := make(map[string]bool)
+	for _, name := range taskFieldNames() {
+		known[name] = true
+	}
+
+	var fields []string
+	for _, part := range strings.Split(s, ",") {
+		name := strings.TrimSpace(part)
+		if name == "" {
+			continue
+		}
+		if !known[name] {
+			return nil, fmt.Errorf(msg("неизвестное поле: %s (доступны: %s)"), name, strings.Join(taskFieldNames(), ", "))
+		}
+		fields = append(fields, name)
+	}
+	if len(fields) == 0 {
+		return nil, errors.New(msg("не указано ни одного поля"))
+	}
+	return fields, nil
+}
+
+// projectTask возвращает только выбранные поля задачи
+// Поля, опущенные в JSON из-за пустого значения, получают nil
+func projectTask(task Task, fields []string) (map[string]interface{}, error) {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	projected := make(map[string]interface{}, len(fields))
+	for _, name := range fields {
+		projected[name] = all[name]
+	}
+	return projected, nil
+}
+
+// writeFieldsJSON записывает задачи JSON-массивом объектов только с выбранными полями
+// Ключи в каждом объекте идут в порядке fields, а не по алфавиту, как у encoding/json
+func writeFieldsJSON(tasks []Task, fields []string, w io.Writer, compact bool) error {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, task := range tasks {
+		projected, err := projectTask(task, fields)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for j, name := range fields {
+			key, _ := json.Marshal(name)
+			value, err := json.Marshal(projected[name])
+			if err != nil {
+				return err
+			}
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(value)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+
+	data := buf.Bytes()
+	if !compact {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, data, "", "  "); err != nil {
+			return err
+		}
+		data = indented.Bytes()
+	}
+
+	_, err := w.Write(append(data, '\n'))
+	return err
+}
+
+// writeNDJSON выводит задачи в формате NDJSON: по одному JSON-объекту в строке
+// Для пустого списка ничего не выводится
+func writeNDJSON(tasks []Task, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, task := range tasks {
+		if err := enc.Encode(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// envelopeVersion — версия схемы конверта NDJSON с метаданными
+const envelopeVersion = 1
+
+// taskEnvelope — задача вместе с версией схемы и файлом, из которого она загружена
+type taskEnvelope struct {
+	Version int    `json:"version"`
+	File    string `json:"file"`
+	Task    Task   `json:"task"`
+}
+
+// writeNDJSONWithMeta выводит задачи в формате NDJSON, оборачивая каждую в конверт с метаданными
+// Для пустого списка ничего не выводится
+func writeNDJSONWithMeta(tasks []Task, file string, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, task := range tasks {
+		if err := enc.Encode(taskEnvelope{Version: envelopeVersion, File: file, Task: task}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportTasksJSON записывает задачи в виде JSON-массива без служебных полей списка
+// Результат остаётся корректным JSON и для пустого списка
+func exportTasksJSON(tasks []Task, w io.Writer) error {
+	return writeTasksJSON(tasks, w, false)
+}
+
+// exportToFile создаёт файл и записывает в него данные с помощью функции write
+func exportToFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// exportText записывает задачи в текстовом виде, применяя шаблон text/template к каждой задаче
+// Пустой шаблон заменяется шаблоном по умолчанию
+func exportText(tl *TodoList, tmpl string, w io.Writer) error {
+	if tmpl == "" {
+		tmpl = defaultTextTemplate
+	}
+
+	t, err := template.New("task").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf(msg("не верный шаблон: %w"), err)
+	}
+
+	for _, task := range tl.Tasks {
+		if err := t.Execute(w, task); err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
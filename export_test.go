@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWriteTasksJSONFormatting(t *testing.T) {
+	tasks := newTestList("a", "b").Tasks
+
+	var compact, pretty bytes.Buffer
+	if err := writeTasksJSON(tasks, &compact, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeTasksJSON(tasks, &pretty, false); err != nil {
+		t.Fatal(err)
+	}
+
+	line := strings.TrimSuffix(compact.String(), "\n")
+	if strings.ContainsAny(line, "\n\t") || strings.Contains(line, ": ") {
+		t.Errorf("compact output has extra whitespace: %q", line)
+	}
+	if !strings.Contains(strings.TrimSpace(pretty.String()), "\n") {
+		t.Errorf("pretty output has no newlines: %q", pretty.String())
+	}
+
+	var fromCompact, fromPretty []Task
+	if err := json.Unmarshal(compact.Bytes(), &fromCompact); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(pretty.Bytes(), &fromPretty); err != nil {
+		t.Fatal(err)
+	}
+	if len(fromCompact) != 2 || len(fromPretty) != 2 || fromCompact[1].Content != fromPretty[1].Content {
+		t.Errorf("outputs differ: %v vs %v", fromCompact, fromPretty)
+	}
+}
+
+func TestWriteTasksJSONEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeTasksJSON(nil, &buf, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "[]\n" {
+		t.Errorf("empty output = %q, want []", got)
+	}
+}
+
+func TestExportTasksJSONFiltered(t *testing.T) {
+	tl := newTestList("a", "b", "c")
+	tl.Tasks[0].Done = true
+	tl.Tasks[1].Tags = []string{"work"}
+	tl.Tasks[2].Tags = []string{"Work"}
+
+	tests := []struct {
+		name string
+		opts FilterOptions
+		want []int
+	}{
+		{"status", FilterOptions{Status: statusPending}, []int{2, 3}},
+		{"tag", FilterOptions{Tag: "WORK"}, []int{2, 3}},
+		{"status and tag", FilterOptions{Status: statusDone, Tag: "work"}, []int{}},
+		{"none", FilterOptions{}, []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := exportTasksJSON(applyFilters(tl, tt.opts), &buf); err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(buf.String(), "next_id") {
+				t.Errorf("export contains next_id: %s", buf.String())
+			}
+
+			var tasks []Task
+			if err := json.Unmarshal(buf.Bytes(), &tasks); err != nil {
+				t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+			}
+			if got := taskIds(tasks); !slices.Equal(got, tt.want) {
+				t.Errorf("exported %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportText(t *testing.T) {
+	tl := newTestList("milk", "bread")
+	tl.Tasks[1].Done = true
+
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{"default", "", "1. [ ] milk\n2. [x] bread\n"},
+		{"custom", "{{.Id}}: {{.Content}}", "1: milk\n2: bread\n"},
+		{"fields", "{{.Content}}{{if .Done}} (done){{end}}", "milk\nbread (done)\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := exportText(tl, tt.tmpl, &buf); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("exportText = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestExportTextInvalidTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	err := exportText(newTestList("milk"), "{{.Id", &buf)
+	if err == nil || !strings.Contains(err.Error(), "unclosed action") {
+		t.Errorf("exportText error = %v, want the parse error", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("output written for an invalid template: %q", buf.String())
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	tl := newTestList("milk", "bread", "eggs")
+	tl.Tasks[1].Notes = "line one\nline two"
+
+	var buf bytes.Buffer
+	if err := writeNDJSON(tl.Tasks, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("lines = %d, want 3:\n%s", len(lines), buf.String())
+	}
+	for i, line := range lines {
+		var task Task
+		if err := json.Unmarshal([]byte(line), &task); err != nil {
+			t.Fatalf("line %d is not JSON: %v", i+1, err)
+		}
+		if task.Id != tl.Tasks[i].Id || task.Notes != tl.Tasks[i].Notes {
+			t.Errorf("line %d = %+v", i+1, task)
+		}
+	}
+}
+
+func TestWriteNDJSONEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeNDJSON(nil, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("output for an empty list = %q", buf.String())
+	}
+}
+
+func TestWriteFieldsJSON(t *testing.T) {
+	fields, err := parseFields(" id, done ,content")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tasks := []Task{{Id: 1, Content: "a", Tags: []string{"x"}}, {Id: 2, Content: "b", Done: true}}
+	var buf bytes.Buffer
+	if err := writeFieldsJSON(tasks, fields, &buf, true); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `[{"id":1,"done":false,"content":"a"},{"id":2,"done":true,"content":"b"}]` + "\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestParseFieldsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"unknown field", "id,title", "неизвестное поле: title"},
+		{"no fields", " , ", "не указано ни одного поля"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseFields(tt.in); err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("parseFields(%q) error = %v, want %q", tt.in, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteNDJSONWithMeta(t *testing.T) {
+	tl := newTestList("milk", "bread")
+	tl.Tasks[1].Tags = []string{"shop"}
+
+	var buf bytes.Buffer
+	if err := writeNDJSONWithMeta(tl.Tasks, "work.json", &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("lines = %d, want 2:\n%s", len(lines), buf.String())
+	}
+	for i, line := range lines {
+		var envelope struct {
+			Version *int            `json:"version"`
+			File    *string         `json:"file"`
+			Task    json.RawMessage `json:"task"`
+		}
+		if err := json.Unmarshal([]byte(line), &envelope); err != nil {
+			t.Fatalf("line %d is not JSON: %v", i+1, err)
+		}
+		if envelope.Version == nil || *envelope.Version != 1 || envelope.File == nil || *envelope.File != "work.json" {
+			t.Errorf("line %d envelope = %s", i+1, line)
+		}
+
+		var task Task
+		if err := json.Unmarshal(envelope.Task, &task); err != nil {
+			t.Fatalf("line %d task: %v", i+1, err)
+		}
+		if task.Id != tl.Tasks[i].Id || task.Content != tl.Tasks[i].Content || !slices.Equal(task.Tags, tl.Tasks[i].Tags) {
+			t.Errorf("line %d task = %+v", i+1, task)
+		}
+	}
+}
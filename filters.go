@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Значения фильтра по статусу
+const (
+	statusPending = "pending"
+	statusDone    = "done"
+)
+
+// FilterOptions описывает условия отбора задач при выводе списка
+// Пустые поля не ограничивают выборку
+type FilterOptions struct {
+	Status  string // Статус задачи: pending или done
+	Tag     string // Тег задачи (без учета регистра)
+	Creator string // Автор задачи (без учета регистра)
+
+	Priority string // Приоритет задачи: high, medium или low
+	Search   string // Подстрока текста задачи (без учета регистра)
+	HasDue   bool   // Только задачи со сроком
+	NoDue    bool   // Только задачи без срока
+	SinceId  int    // Только задачи с ID больше указанного (0 — без ограничения)
+}
+
+// validateStatus проверяет значение фильтра по статусу
+func validateStatus(status string) error {
+	switch status {
+	case "", statusPending, statusDone:
+		return nil
+	default:
+		return fmt.Errorf(msg("Ошибка: не верный статус %q (ожидается pending или done)"), status)
+	}
+}
+
+// hasDue проверяет, задан ли у задачи срок
+func hasDue(task Task) bool {
+	return task.DueDate != ""
+}
+
+// noDue проверяет, что у задачи нет срока
+func noDue(task Task) bool {
+	return !hasDue(task)
+}
+
+// matchFilters проверяет, подходит ли задача под все заданные условия
+func matchFilters(task Task, opts FilterOptions) bool {
+	if opts.Status == statusPending && task.Done || opts.Status == statusDone && !task.Done {
+		return false
+	}
+
+	if opts.Tag != "" && !hasTag(task, opts.Tag) {
+		return false
+	}
+
+	if opts.SinceId > 0 && task.Id <= opts.SinceId {
+		return false
+	}
+
+	if opts.Priority != "" && !hasPriority(task, opts.Priority) {
+		return false
+	}
+
+	if opts.HasDue && !hasDue(task) || opts.NoDue && !noDue(task) {
+		return false
+	}
+
+	if opts.Search != "" && !strings.Contains(strings.ToLower(task.Content), strings.ToLower(opts.Search)) {
+		return false
+	}
+
+	if opts.Creator != "" && !strings.EqualFold(task.CreatedBy, opts.Creator) {
+		return false
+	}
+
+	return true
+}
+
+// applyFilters возвращает задачи, подходящие под условия отбора, в исходном порядке
+func applyFilters(tl *TodoList, opts FilterOptions) []Task {
+	var tasks []Task
+	for _, task := range tl.Tasks {
+		if matchFilters(task, opts) {
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks
+}
+
+// matchingIds возвращает ID задач, подходящих под фильтры, в порядке списка
+func matchingIds(tl *TodoList, opts FilterOptions) []int {
+	var ids []int
+	for _, task := range applyFilters(tl, opts) {
+		ids = append(ids, task.Id)
+	}
+	return ids
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestApplyFiltersCreator(t *testing.T) {
+	tl := newTestList("a", "b", "c")
+	tl.Tasks[0].CreatedBy = "anna"
+	tl.Tasks[1].CreatedBy = "Anna"
+	tl.Tasks[2].CreatedBy = "ivan"
+
+	if got := taskIds(applyFilters(tl, FilterOptions{Creator: "ANNA"})); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("creator filter = %v, want [1 2]", got)
+	}
+}
+
+func TestApplyFiltersDue(t *testing.T) {
+	tl := newTestList("a", "b", "c", "d")
+	tl.Tasks[0].DueDate = "2024-06-10"
+	tl.Tasks[1].DueDate = "2024-06-11"
+	markDone(&tl.Tasks[1], testNow)
+	markDone(&tl.Tasks[3], testNow)
+
+	tests := []struct {
+		name string
+		opts FilterOptions
+		want []int
+	}{
+		{"has due", FilterOptions{HasDue: true}, []int{1, 2}},
+		{"no due", FilterOptions{NoDue: true}, []int{3, 4}},
+		{"has due and pending", FilterOptions{HasDue: true, Status: statusPending}, []int{1}},
+		{"no due and pending", FilterOptions{NoDue: true, Status: statusPending}, []int{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := taskIds(applyFilters(tl, tt.opts)); !slices.Equal(got, tt.want) {
+				t.Errorf("applyFilters = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchingIds(t *testing.T) {
+	tl := newTestList("a", "b", "c", "d")
+	tl.Tasks[0].Tags = []string{"work"}
+	tl.Tasks[2].Tags = []string{"Work"}
+	markDone(&tl.Tasks[2], testNow)
+	markDone(&tl.Tasks[3], testNow)
+
+	tests := []struct {
+		name string
+		opts FilterOptions
+		want []int
+	}{
+		{"tag", FilterOptions{Tag: "work"}, []int{1, 3}},
+		{"status", FilterOptions{Status: statusDone}, []int{3, 4}},
+		{"tag and status", FilterOptions{Tag: "work", Status: statusPending}, []int{1}},
+		{"nothing matches", FilterOptions{Tag: "home"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchingIds(tl, tt.opts); !slices.Equal(got, tt.want) {
+				t.Errorf("matchingIds = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyFiltersSinceId(t *testing.T) {
+	tl := newTestList("a", "b", "c", "d", "e")
+	tl.Tasks[3].Done = true
+
+	tests := []struct {
+		name string
+		opts FilterOptions
+		want []int
+	}{
+		{"mid range", FilterOptions{SinceId: 3}, []int{4, 5}},
+		{"beyond max", FilterOptions{SinceId: 10}, nil},
+		{"zero returns all", FilterOptions{}, []int{1, 2, 3, 4, 5}},
+		{"with status", FilterOptions{SinceId: 3, Status: "pending"}, []int{5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := taskIds(applyFilters(tl, tt.opts)); !slices.Equal(got, tt.want) {
+				t.Errorf("applyFilters(%+v) = %v, want %v", tt.opts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchFiltersWithoutSinceId(t *testing.T) {
+	// Задачи с некорректным ID (например, из повреждённого файла) не должны пропадать без --since-id
+	for _, id := range []int{0, -1} {
+		if !matchFilters(Task{Id: id, Content: "broken"}, FilterOptions{}) {
+			t.Errorf("task with id %d filtered out without SinceId", id)
+		}
+	}
+	if matchFilters(Task{Id: 0}, FilterOptions{SinceId: 1}) {
+		t.Error("task with id 0 passed SinceId 1")
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const maxHistory = 50 // Максимальное количество событий в истории задачи
+
+// Типы событий в истории задачи
+const (
+	eventAdd      = "add"
+	eventToggle   = "toggle"
+	eventEdit     = "edit"
+	eventComplete = "complete"
+)
+
+// Event описывает одно изменение задачи
+type Event struct {
+	Type string `json:"type"` // Тип события: add, toggle, edit, complete
+	At   string `json:"at"`   // Дата и время события
+}
+
+// recordEvent добавляет событие в историю задачи
+// Хранятся только последние maxHistory событий
+func recordEvent(task *Task, kind string, now time.Time) {
+	task.History = append(task.History, Event{Type: kind, At: now.Format(timeLayout)})
+	if len(task.History) > maxHistory {
+		task.History = append([]Event(nil), task.History[len(task.History)-maxHistory:]...)
+	}
+}
+
+// eventTitle возвращает описание типа события для вывода
+func eventTitle(kind string) string {
+	switch kind {
+	case eventAdd:
+		return msg("добавлена")
+	case eventToggle:
+		return msg("статус изменён")
+	case eventEdit:
+		return msg("изменена")
+	case eventComplete:
+		return msg("выполнена")
+	default:
+		return kind
+	}
+}
+
+// printHistory выводит историю изменений задачи с указанным ID
+func printHistory(tl *TodoList, id int, w io.Writer) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	task := tl.Tasks[index]
+	if len(task.History) == 0 {
+		fmt.Fprintf(w, msg("История задачи #%d пуста\n"), id)
+		return nil
+	}
+
+	fmt.Fprintf(w, msg("История задачи #%d:\n"), id)
+	for _, e := range task.History {
+		fmt.Fprintf(w, "%s %s\n", e.At, eventTitle(e.Type))
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestToggleRecordsHistory(t *testing.T) {
+	tl := newTestList("a")
+	at := testNow.Format(timeLayout)
+
+	if _, err := toggleStatus(tl, 1, false, testNow); err != nil {
+		t.Fatal(err)
+	}
+
+	history := tl.Tasks[0].History
+	if len(history) != 1 || history[0] != (Event{Type: eventToggle, At: at}) {
+		t.Errorf("history = %+v", history)
+	}
+}
+
+func TestRecordEventBounded(t *testing.T) {
+	var task Task
+	for i := 0; i < maxHistory+10; i++ {
+		recordEvent(&task, eventEdit, testNow.Add(time.Duration(i)*time.Minute))
+	}
+
+	if len(task.History) != maxHistory {
+		t.Fatalf("history length = %d, want %d", len(task.History), maxHistory)
+	}
+	if got, want := task.History[0].At, testNow.Add(10*time.Minute).Format(timeLayout); got != want {
+		t.Errorf("oldest kept event at %s, want %s", got, want)
+	}
+}
+
+func TestPrintHistory(t *testing.T) {
+	tl := newTestList("a")
+	tl.Tasks[0].History = []Event{{Type: eventAdd, At: "2024-06-01 10:00:00"}, {Type: eventComplete, At: "2024-06-01 11:00:00"}}
+
+	var buf bytes.Buffer
+	if err := printHistory(tl, 1, &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "История задачи #1:\n2024-06-01 10:00:00 добавлена\n2024-06-01 11:00:00 выполнена\n"
+	if got := buf.String(); got != want {
+		t.Errorf("printHistory = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// runCompleteHook запускает внешнюю команду после выполнения задачи
+// ID и текст задачи передаются аргументами, а также через переменные окружения TODO_ID и TODO_CONTENT
+func runCompleteHook(command string, task Task) error {
+	cmd := exec.Command(command, strconv.Itoa(task.Id), task.Content)
+	cmd.Env = append(os.Environ(),
+		"TODO_ID="+strconv.Itoa(task.Id),
+		"TODO_CONTENT="+task.Content,
+	)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// notifyCompleted запускает хук завершения для задачи с указанным ID, если она выполнена
+// Ошибки хука выводятся в stderr и не влияют на результат команды
+func notifyCompleted(hook string, tl *TodoList, id int) {
+	if hook == "" {
+		return
+	}
+
+	index := findTaskIndex(tl, id)
+	if index == -1 || !tl.Tasks[index].Done {
+		return
+	}
+
+	if err := runCompleteHook(hook, tl.Tasks[index]); err != nil {
+		fmt.Fprintf(os.Stderr, msg("Ошибка выполнения хука %s: %v\n"), hook, err)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeHook создаёт скрипт, который записывает аргументы и переменные окружения в файл
+func fakeHook(t *testing.T) (command, logPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	logPath = filepath.Join(dir, "calls.log")
+	command = filepath.Join(dir, "hook.sh")
+	script := "#!/bin/sh\necho \"$1|$2|$TODO_ID|$TODO_CONTENT\" >> " + logPath + "\n"
+	if err := os.WriteFile(command, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return command, logPath
+}
+
+func TestNotifyCompletedRunsHook(t *testing.T) {
+	command, logPath := fakeHook(t)
+	tl := newTestList("pending", "Полить цветы")
+	tl.Tasks[1].Done = true
+
+	notifyCompleted(command, tl, 2)
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("hook was not run: %v", err)
+	}
+	if got, want := strings.TrimSpace(string(data)), "2|Полить цветы|2|Полить цветы"; got != want {
+		t.Errorf("hook call = %q, want %q", got, want)
+	}
+}
+
+func TestNotifyCompletedSkips(t *testing.T) {
+	command, logPath := fakeHook(t)
+	tl := newTestList("pending")
+
+	notifyCompleted(command, tl, 1)
+	notifyCompleted(command, tl, 7)
+	notifyCompleted("", tl, 1)
+
+	if _, err := os.Stat(logPath); !os.IsNotExist(err) {
+		t.Errorf("hook ran for a pending or missing task: %v", err)
+	}
+}
+
+func TestRunCompleteHookFailure(t *testing.T) {
+	if err := runCompleteHook(filepath.Join(t.TempDir(), "missing"), Task{Id: 1}); err == nil {
+		t.Error("expected an error for a missing hook command")
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"html/template"
+	"io"
+)
+
+// htmlFuncs — функции шаблона для вывода подписей на текущем языке
+var htmlFuncs = template.FuncMap{
+	"msg":  msg,
+	"lang": func() string { return lang },
+}
+
+// htmlReport — шаблон HTML-страницы со списком задач
+// html/template экранирует текст задач, поэтому разметка в нём выводится как текст
+var htmlReport = template.Must(template.New("report").Funcs(htmlFuncs).Parse(`<!DOCTYPE html>
+<html lang="{{lang}}">
+<head>
+<meta charset="utf-8">
+<title>{{msg "Список задач"}}</title>
+<style>
+body { font-family: sans-serif; max-width: 40em; margin: 2em auto; }
+ul { list-style: none; padding: 0; }
+li { padding: 0.3em 0; }
+.done { color: #888; text-decoration: line-through; }
+.meta { color: #888; font-size: 0.85em; }
+</style>
+</head>
+<body>
+<h1>{{msg "Список задач"}}</h1>
+{{if .}}<ul>
+{{range .}}<li><input type="checkbox" disabled{{if .Done}} checked{{end}}> <span{{if .Done}} class="done"{{end}}>{{.Content}}</span> <span class="meta">{{printf (msg "#%d, создана: %s") .Id .CreatedAt}}{{if .DueDate}}{{printf (msg ", срок: %s") .DueDate}}{{end}}</span></li>
+{{end}}</ul>
+{{else}}<p>{{msg "Список задач пуст"}}</p>
+{{end}}</body>
+</html>
+`))
+
+// exportHTML записывает задачи в виде самостоятельной HTML-страницы
+func exportHTML(tl *TodoList, w io.Writer) error {
+	return htmlReport.Execute(w, tl.Tasks)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExportHTMLEscapesContent(t *testing.T) {
+	tl := newTestList(`<script>alert("x")</script> & more`)
+
+	var buf bytes.Buffer
+	if err := exportHTML(tl, &buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	if strings.Contains(out, "<script>") {
+		t.Errorf("task content not escaped:\n%s", out)
+	}
+	if !strings.Contains(out, "&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; more") {
+		t.Errorf("escaped content missing:\n%s", out)
+	}
+}
+
+func TestExportHTMLCheckboxes(t *testing.T) {
+	tl := newTestList("pending", "done")
+	tl.Tasks[1].Done = true
+
+	var buf bytes.Buffer
+	if err := exportHTML(tl, &buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	if !strings.HasPrefix(out, "<!DOCTYPE html>") || !strings.Contains(out, "</html>") {
+		t.Errorf("not a complete HTML document:\n%s", out)
+	}
+	if got := strings.Count(out, `type="checkbox"`); got != 2 {
+		t.Errorf("checkboxes = %d, want 2", got)
+	}
+	if got := strings.Count(out, " checked>"); got != 1 {
+		t.Errorf("checked boxes = %d, want 1", got)
+	}
+}
+
+func TestExportHTMLTranslated(t *testing.T) {
+	useLang(t, langEnglish)
+	tl := newTestList("milk")
+	tl.Tasks[0].DueDate = "2024-06-10"
+
+	var buf bytes.Buffer
+	if err := exportHTML(tl, &buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{`<html lang="en">`, "<title>Task list</title>", "#1, created: ", ", due: 2024-06-10"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.ContainsAny(out, "абвгдеёжзийклмнопрстуфхцчшщъыьэюя") {
+		t.Errorf("untranslated labels left:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := exportHTML(&TodoList{}, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "<p>The task list is empty</p>") {
+		t.Errorf("empty list output:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	langRussian = "ru"
+	langEnglish = "en"
+)
+
+var lang = langRussian // Язык сообщений
+
+// catalogs содержит переводы сообщений; ключом служит русский текст
+var catalogs = map[string]map[string]string{
+	langEnglish: messagesEnglish,
+}
+
+// msg возвращает сообщение на текущем языке
+// Для русского языка и неизвестных ключей возвращается сам ключ
+func msg(key string) string {
+	if translated, ok := catalogs[lang][key]; ok {
+		return translated
+	}
+	return key
+}
+
+// resolveLang определяет язык сообщений по флагу --lang или переменной окружения LANG
+// Явно указанный язык должен быть ru или en; по LANG выбирается английский
+// для локалей en_*, иначе используется русский
+func resolveLang(flagLang string, getenv func(string) string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(flagLang)) {
+	case langRussian:
+		return langRussian, nil
+	case langEnglish:
+		return langEnglish, nil
+	case "":
+	default:
+		return langRussian, fmt.Errorf(msg("Ошибка: неизвестный язык %q (ожидается ru или en)"), flagLang)
+	}
+
+	if strings.HasPrefix(strings.ToLower(getenv("LANG")), langEnglish) {
+		return langEnglish, nil
+	}
+	return langRussian, nil
+}
+
+// messagesEnglish — английские переводы сообщений
+var messagesEnglish = map[string]string{
+	"Список задач":     "Task list",
+	"#%d, создана: %s": "#%d, created: %s",
+	"Ошибка: новый тег не может быть пустым": "Error: the new tag cannot be empty",
+	"Ошибка: у задачи #%d нет тега %s":       "Error: task #%d has no tag %s",
+	"Теги исправлены у задач: %d\n":          "Tags cleaned up in tasks: %d\n",
+	"— нет —":              "— none —",
+	"Сводка задач на %s\n": "Task digest for %s\n",
+	"Выполнено сегодня:":   "Completed today:",
+	"Просрочено:":          "Overdue:",
+	"- #%d %s (срок: %s)":  "- #%d %s (due: %s)",
+	"Срок сегодня:":        "Due today:",
+	"Ошибка: задача #%d защищена, используйте --unlock или --force": "Error: task #%d is locked, use --unlock or --force",
+	"Задача #%d защищена\n":                                              "Task #%d locked\n",
+	"Защита задачи #%d снята\n":                                          "Task #%d unlocked\n",
+	"Задача #%d пропущена: защищена\n":                                   "Task #%d skipped: locked\n",
+	"Ошибка: защищены задачи %s, используйте --unlock или --force":       "Error: tasks %s are locked, use --unlock or --force",
+	"Защищена от удаления и изменения статуса":                           "Locked against deletion and status changes",
+	"Ошибка: нечего дописывать":                                          "Error: nothing to append",
+	"Приоритет изменён у задач: %d\n":                                    "Priority changed for tasks: %d\n",
+	"Задачи упорядочены по приоритету":                                   "Tasks reordered by priority",
+	"Ошибка вывода статистики: %v\n":                                     "Error printing statistics: %v\n",
+	"Ошибка: заметка не может быть пустой":                               "Error: the note cannot be empty",
+	"Ошибка: заметка не должна превышать %d символов":                    "Error: the note must not exceed %d characters",
+	"Все теги используются":                                              "All tags are in use",
+	"Неиспользуемые теги:":                                               "Unused tags:",
+	"Ошибка: список задач заморожен, используйте --unfreeze или --force": "Error: the task list is frozen, use --unfreeze or --force",
+	"список задач заморожен, используйте --unfreeze или --force":         "the task list is frozen, use --unfreeze or --force",
+	"Ошибка: --freeze и --unfreeze нельзя указывать вместе":              "Error: --freeze and --unfreeze cannot be used together",
+	"Список задач заморожен":                                             "Task list frozen",
+	"Список задач разморожен":                                            "Task list unfrozen",
+	"Ошибка: количество должно быть положительным":                       "Error: the count must be positive",
+	"Популярные теги:":                                                   "Top tags:",
+	"Нет задач с тегами":                                                 "No tagged tasks",
+	"Исполнители с наибольшим числом задач:":                             "Top assignees:",
+	"Нет задач с исполнителями":                                          "No assigned tasks",
+	"Ошибка: неверное время %q, ожидается формат %s\n":                   "Error: invalid time %q, expected format %s\n",
+	"Задача #%d отмечена выполненной %s\n":                               "Task #%d marked done at %s\n",
+	"неизвестное поле: %s (доступны: %s)":                                "unknown field: %s (available: %s)",
+	"не указано ни одного поля":                                          "no fields given",
+	"Перенумеровано задач: %d\n":                                         "Tasks renumbered: %d\n",
+	"Ошибка: текст совпадает с несколькими задачами: %s":                 "Error: the content matches several tasks: %s",
+	"Приоритет повышен у задач: %d\n":                                    "Priority raised for tasks: %d\n",
+	"в списке %d задач, что больше ограничения %d":                       "the list has %d tasks, more than the limit of %d",
+	"Выполнено задач по исполнителям:":                                   "Completed tasks by assignee:",
+	"без исполнителя":                                                    "unassigned",
+	"Исполнитель задачи #%d обновлён\n":                                  "Assignee of task #%d updated\n",
+	"Исполнитель: %s\n":                                                  "Assignee: %s\n",
+	"Ошибка: не верное регулярное выражение: %w":                         "Error: invalid regular expression: %w",
+	"Ошибка: --has-due и --no-due нельзя указывать вместе":               "Error: --has-due and --no-due cannot be used together",
+	"Списки совпадают":                                                   "The lists are identical",
+	"Только в текущем списке:":                                           "Only in the current list:",
+	"Только в %s:\n":                                                     "Only in %s:\n",
+	"Изменённые задачи:":                                                 "Changed tasks:",
+	"Задача #%d пропущена: ждёт %s\n":                                    "Task #%d skipped: waiting on %s\n",
+	"Задач: %d\n": "Tasks: %d\n",
+	"Слов: %d\n":  "Words: %d\n",
+	"В среднем слов на задачу: %.1f\n":                                                   "Average words per task: %.1f\n",
+	"Ошибка: не верный период повторения %q (ожидается daily, weekly, monthly или none)": "Error: invalid recurrence %q (expected daily, weekly, monthly or none)",
+	"Повторение задачи #%d обновлено\n":                                                  "Recurrence of task #%d updated\n",
+	"Окончание повторения задачи #%d обновлено\n":                                        "Recurrence end of task #%d updated\n",
+	"Повторение: %s\n":                                      "Repeats: %s\n",
+	"Повторять до: %s\n":                                    "Repeat until: %s\n",
+	"Ошибка редактора: %v\n":                                "Editor error: %v\n",
+	"Нет выполненных задач":                                 "No completed tasks",
+	"Выполнено задач по часам:":                             "Tasks completed by hour:",
+	"Ошибка: нет задачи на позиции %d":                      "Error: no task at position %d",
+	"Ошибка: ID должен быть положительным":                  "Error: ID must be positive",
+	"Ошибка: ID %d уже занят":                               "Error: ID %d is already taken",
+	"Задаче на позиции %d назначен ID %d\n":                 "Task at position %d now has ID %d\n",
+	"Пустых задач нет":                                      "No empty tasks",
+	"Пустые задачи: %s\n":                                   "Empty tasks: %s\n",
+	"Удалено пустых задач: %d\n":                            "Empty tasks deleted: %d\n",
+	"Ошибка: не верная оценка %q (ожидается число часов)":   "Error: invalid estimate %q (expected a number of hours)",
+	"Осталось: %.1f ч.\n":                                   "Remaining: %.1f h\n",
+	"Скорость: %.1f ч. в день\n":                            "Velocity: %.1f h per day\n",
+	"До завершения: 0 дн.":                                  "Until done: 0 d",
+	"До завершения: неизвестно":                             "Until done: unknown",
+	"До завершения: %.1f дн.\n":                             "Until done: %.1f d\n",
+	"Оценка задачи #%d обновлена\n":                         "Estimate of task #%d updated\n",
+	"Оценка: %g ч.\n":                                       "Estimate: %g h\n",
+	"Ошибка: тег не может быть пустым":                      "Error: tag cannot be empty",
+	"Тег %s добавлен задачам: %d\n":                         "Tag %s added to tasks: %d\n",
+	"Ошибка: не верное количество копий":                    "Error: invalid number of backups",
+	"Ошибка создания резервной копии: %v\n":                 "Error creating backup: %v\n",
+	"Резервная копия сохранена в %s\n":                      "Backup saved to %s\n",
+	"Ошибка удаления резервных копий: %v\n":                 "Error deleting backups: %v\n",
+	"Удалено резервных копий: %d\n":                         "Backups deleted: %d\n",
+	"количество копий не может быть отрицательным":          "the number of backups cannot be negative",
+	"Ошибка: неизвестный язык %q (ожидается ru или en)":     "Error: unknown language %q (expected ru or en)",
+	" (создана: %s)":                                        " (created: %s)",
+	"%d дн.":                                                "%d d",
+	"%d мин.":                                               "%d min",
+	"%d ч.":                                                 "%d h",
+	"%d, %s (выполнена: %s)\n":                              "%d, %s (completed: %s)\n",
+	"%d, %s (ждёт: %s)\n":                                   "%d, %s (waiting on: %s)\n",
+	"%d, %s (создана: %s)\n":                                "%d, %s (created: %s)\n",
+	"%d, %s (срок: %s)\n":                                   "%d, %s (due: %s)\n",
+	", выполнена: %s":                                       ", completed: %s",
+	", приоритет: %s":                                       ", priority: %s",
+	", срок: %s":                                            ", due: %s",
+	"ID %d встречается несколько раз":                       "ID %d appears more than once",
+	"next_id (%d) должен быть больше максимального ID (%d)": "next_id (%d) must be greater than the highest ID (%d)",
+	"Автор: %s\n":                                           "Author: %s\n",
+	"Без тегов:":                                            "Untagged:",
+	"Внимание: порядок задач в файле изменён":               "Warning: the task order in the file has been changed",
+	"Время создания задачи #%d обновлено\n":                 "Creation time of task #%d updated\n",
+	"Все задачи отмечены как выполненные":                   "All tasks marked as done",
+	"Все задачи очищены":                                    "All tasks cleared",
+	"Всего: %d\n":                                           "Total: %d\n",
+	"Всё сделано!":                                          "All done!",
+	"Выполнена: %s\n":                                       "Completed: %s\n",
+	"Выполненные задачи:":                                   "Completed tasks:",
+	"Выполнено задач: %d\n":                                 "Tasks completed: %d\n",
+	"Выполнено подзадач: %d из %d\n":                        "Subtasks done: %d of %d\n",
+	"Выполнено: %d\n":                                       "Done: %d\n",
+	"Выполнены:":                                            "Completed:",
+	"Дней подряд с выполненными задачами: %d\n":             "Days in a row with completed tasks: %d\n",
+	"Добавлена задача %d: %s\n":                             "Added task %d: %s\n",
+	"Добавлена подзадача к задаче #%d: %s\n":                "Added subtask to task #%d: %s\n",
+	"Добавлено задач: %d из %d\n":                           "Tasks added: %d of %d\n",
+	"Добавлены:":                                            "Added:",
+	"Заблокированные задачи:":                               "Blocked tasks:",
+	"Зависит от: %s\n":                                      "Depends on: %s\n",
+	"Задача #%d больше не зависит от #%d\n":                 "Task #%d no longer depends on #%d\n",
+	"Задача #%d была удалена\n":                             "Task #%d was deleted\n",
+	"Задача #%d изменена: %s\n":                             "Task #%d changed: %s\n",
+	"Задача #%d изменена\n":                                 "Task #%d changed\n",
+	"Задача #%d отмечена как %s\n":                          "Task #%d marked as %s\n",
+	"Задача #%d отмечена как выполнено\n":                   "Task #%d marked as done\n",
+	"Задача #%d перемещена\n":                               "Task #%d moved\n",
+	"Задача #%d пропущена: текст стал бы некорректным\n":    "Task #%d skipped: content would become invalid\n",
+	"Задача #%d теперь зависит от #%d\n":                    "Task #%d now depends on #%d\n",
+	"Задача #%d уже выполнена":                              "Task #%d is already done",
+	"Задача #%d уже выполнена\n":                            "Task #%d is already done\n",
+	"Задача #%d\n":                                          "Task #%d\n",
+	"Задача %q пропущена: %v\n":                             "Task %q skipped: %v\n",
+	"Задача не найдена":                                     "Task not found",
+	"Задачи отсортированы по ключу %s\n":                    "Tasks sorted by %s\n",
+	"Займитесь задачей #%d: %s\n":                           "Work on task #%d: %s\n",
+	"Заметки задачи #%d обновлены\n":                        "Notes of task #%d updated\n",
+	"Заметки: %s\n":                                         "Notes: %s\n",
+	"Изменений с прошлого просмотра нет":                    "No changes since the last check",
+	"Изменения с %s:\n":                                     "Changes since %s:\n",
+	"Изменено задач: %d\n":                                  "Tasks changed: %d\n",
+	"Импортировано задач: %d\n":                             "Tasks imported: %d\n",
+	"Интерактивный режим. Введите help для списка команд, quit для выхода": "Interactive mode. Type help for a list of commands, quit to exit",
+	"История задачи #%d пуста\n": "History of task #%d is empty\n",
+	"История задачи #%d:\n":      "History of task #%d:\n",
+	"Команды: add <текст>, list, done <id>, toggle <id>, rm <id>, rename <id> <текст>, help, quit": "Commands: add <text>, list, done <id>, toggle <id>, rm <id>, rename <id> <text>, help, quit",
+	"Не выполнено: %d\n":                                                                        "Pending: %d\n",
+	"Нет заблокированных задач":                                                                 "No blocked tasks",
+	"Нет задач, выполненных за этот период":                                                     "No tasks completed in this period",
+	"Нет задач, готовых к выполнению":                                                           "No tasks ready to start",
+	"Нет невыполненных задач":                                                                   "No pending tasks",
+	"Нет просроченных задач":                                                                    "No overdue tasks",
+	"Нормализовано задач: %d\n":                                                                 "Tasks normalized: %d\n",
+	"Объединено дубликатов: %d\n":                                                               "Duplicates merged: %d\n",
+	"Ошибка вывода задач: %v\n":                                                                 "Error printing tasks: %v\n",
+	"Ошибка выполнения хука %s: %v\n":                                                           "Error running hook %s: %v\n",
+	"Ошибка загрузки задач: %v\n":                                                               "Error loading tasks: %v\n",
+	"Ошибка сохранения задач: %v\n":                                                             "Error saving tasks: %v\n",
+	"Ошибка чтения файла команд: %v\n":                                                          "Error reading command file: %v\n",
+	"Ошибка чтения файла: %v\n":                                                                 "Error reading file: %v\n",
+	"Ошибка экспорта задач: %v\n":                                                               "Error exporting tasks: %v\n",
+	"Ошибка: %v\n":                                                                              "Error: %v\n",
+	"Ошибка: выполнено %.0f%% задач, что ниже порога %.0f%%":                                    "Error: %.0f%% of tasks done, below the %.0f%% threshold",
+	"Ошибка: задача не может зависеть от самой себя":                                            "Error: a task cannot depend on itself",
+	"Ошибка: задача с таким заголовком уже существует":                                          "Error: a task with this title already exists",
+	"Ошибка: имя профиля не должно содержать разделители пути":                                  "Error: profile name must not contain path separators",
+	"Ошибка: имя профиля не может быть пустым":                                                  "Error: profile name cannot be empty",
+	"Ошибка: количество задач должно быть положительным":                                        "Error: the number of tasks must be positive",
+	"Ошибка: не верная дата %q":                                                                 "Error: invalid date %q",
+	"Ошибка: не верный JSON: %w":                                                                "Error: invalid JSON: %w",
+	"Ошибка: не верный id":                                                                      "Error: invalid id",
+	"Ошибка: не верный диапазон %q":                                                             "Error: invalid range %q",
+	"Ошибка: не верный ключ сортировки %q (ожидается id, created, content, due или status)":     "Error: invalid sort key %q (expected id, created, content, due or status)",
+	"Ошибка: не верный номер подзадачи":                                                         "Error: invalid subtask number",
+	"Ошибка: не верный приоритет %q (ожидается high, medium или low)":                           "Error: invalid priority %q (expected high, medium or low)",
+	"Ошибка: не верный регистр %q (ожидается title, sentence или lower)":                        "Error: invalid case %q (expected title, sentence or lower)",
+	"Ошибка: не верный статус %q (ожидается pending или done)":                                  "Error: invalid status %q (expected pending or done)",
+	"Ошибка: не верный формат времени %q (используйте макет Go, например \"02.01.2006 15:04\")": "Error: invalid time format %q (use a Go layout, e.g. \"02.01.2006 15:04\")",
+	"Ошибка: не удалось сохранить отметку просмотра: %v\n":                                      "Error: could not save the last-run marker: %v\n",
+	"Ошибка: не указан id":                                                                      "Error: id is missing",
+	"Ошибка: неизвестная команда %q":                                                            "Error: unknown command %q",
+	"Ошибка: неизвестный цвет %q (допустимы red, green, yellow, blue, magenta, cyan)":           "Error: unknown color %q (allowed: red, green, yellow, blue, magenta, cyan)",
+	"Ошибка: новый текст задачи не может быть пустым":                                           "Error: task content cannot be empty",
+	"Ошибка: подзадача %d не найдена":                                                           "Error: subtask %d not found",
+	"Ошибка: профиль %s не найден":                                                              "Error: profile %s not found",
+	"Ошибка: профиль %s уже существует":                                                         "Error: profile %s already exists",
+	"Ошибка: текст задачи не должен превышать %d символов\n":                                    "Error: task content must not exceed %d characters\n",
+	"Первый просмотр, показаны все задачи":                                                      "First check, showing all tasks",
+	"Подзадача %d задачи #%d изменена\n":                                                        "Subtask %d of task #%d changed\n",
+	"Подзадачи:":               "Subtasks:",
+	"Похожие задачи:":          "Similar tasks:",
+	"Похожих задач не найдено": "No similar tasks found",
+	"Предупреждение: задача %q уже существует\n": "Warning: task %q already exists\n",
+	"Приоритет задачи #%d обновлён\n":            "Priority of task #%d updated\n",
+	"Приоритет: %s\n":                            "Priority: %s\n",
+	"Проблема: %s\n":                             "Problem: %s\n",
+	"Просроченные задачи:":                       "Overdue tasks:",
+	"Просрочено: %d\n":                           "Overdue: %d\n",
+	"Профиль %s переименован в %s\n":             "Profile %s renamed to %s\n",
+	"Самая старая задача #%d: %s (ждёт %s)\n":    "Oldest task #%d: %s (waiting %s)\n",
+	"Создана: %s\n":                              "Created: %s\n",
+	"Список задач пуст":                          "The task list is empty",
+	"Список задач:":                              "Tasks:",
+	"Срок задачи #%d обновлён\n":                 "Due date of task #%d updated\n",
+	"Срок: %s\n":                                 "Due: %s\n",
+	"Статистика задач:":                          "Task statistics:",
+	"Статус всех задач изменён: выполнено %d, не выполнено %d\n": "Status of all tasks inverted: done %d, pending %d\n",
+	"Статус: %s\n":                   "Status: %s\n",
+	"Строк с ошибками: %d\n":         "Lines with errors: %d\n",
+	"Строка %d: ":                    "Line %d: ",
+	"Теги задачи #%d: %s\n":          "Tags of task #%d: %s\n",
+	"Теги: %s\n":                     "Tags: %s\n",
+	"Текст: %s\n":                    "Content: %s\n",
+	"Удалено задач с тегом %s: %d\n": "Tasks with tag %s deleted: %d\n",
+	"Цвет: %s\n":                     "Color: %s\n",
+	"Экспортировано задач: %d\n":     "Tasks exported: %d\n",
+	"выполнена":                      "completed",
+	"выполнено":                      "done",
+	"добавлена":                      "added",
+	"задача #%d выполнена, но не имеет времени завершения": "task #%d is done but has no completion time",
+	"задача #%d не выполнена, но имеет время завершения":   "task #%d is pending but has a completion time",
+	"задача #%d: не верная дата создания %q":               "task #%d: invalid creation time %q",
+	"задача #%d: не верное время завершения %q":            "task #%d: invalid completion time %q",
+	"задача #%d: не верное время события %q":               "task #%d: invalid event time %q",
+	"задача #%d: не верный срок %q":                        "task #%d: invalid due date %q",
+	"изменена":                   "edited",
+	"меньше минуты":              "less than a minute",
+	"не верная отметка в %s: %w": "invalid marker in %s: %w",
+	"не верный шаблон: %w":       "invalid template: %w",
+	"не выполнено":               "pending",
+	"не удалось записать %s: %w": "could not write %s: %w",
+	"не удалось создать %s: %w":  "could not create %s: %w",
+	"статус изменён":             "status changed",
+}
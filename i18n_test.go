@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+)
+
+// useLang переключает язык сообщений до конца теста
+func useLang(t *testing.T, l string) {
+	old := lang
+	lang = l
+	t.Cleanup(func() { lang = old })
+}
+
+func TestMsgSwitchesLocale(t *testing.T) {
+	useLang(t, langEnglish)
+	if got := msg("Задача не найдена"); got != "Task not found" {
+		t.Errorf("english msg = %q", got)
+	}
+	if got := msg("нет такого ключа"); got != "нет такого ключа" {
+		t.Errorf("unknown key = %q, want the key itself", got)
+	}
+
+	lang = langRussian
+	if got := msg("Задача не найдена"); got != "Задача не найдена" {
+		t.Errorf("russian msg = %q", got)
+	}
+}
+
+func TestResolveLang(t *testing.T) {
+	env := func(value string) func(string) string {
+		return func(string) string { return value }
+	}
+
+	tests := []struct {
+		flag    string
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{"", "", langRussian, false},
+		{"", "en_US.UTF-8", langEnglish, false},
+		{"", "ru_RU.UTF-8", langRussian, false},
+		{"EN", "ru_RU.UTF-8", langEnglish, false},
+		{"ru", "en_US.UTF-8", langRussian, false},
+		{"de", "", langRussian, true},
+	}
+
+	for _, tt := range tests {
+		got, err := resolveLang(tt.flag, env(tt.env))
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("resolveLang(%q, LANG=%q) = %q, %v; want %q, error %v", tt.flag, tt.env, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
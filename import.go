@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// jsonImportItem описывает задачу во внешнем JSON-массиве
+// Обязательно только поле content, неизвестные поля игнорируются
+type jsonImportItem struct {
+	Content string   `json:"content"`
+	Done    bool     `json:"done"`
+	Tags    []string `json:"tags"`
+}
+
+// duplicateContents возвращает тексты входящих задач, которые уже есть в списке
+// или повторяются среди самих входящих задач (без учета регистра)
+// Прошлые повторения повторяющихся задач в списке не учитываются
+func duplicateContents(tl *TodoList, incoming []Task) []string {
+	var dups []string
+	seen := append([]Task(nil), tl.Tasks...)
+	for _, task := range incoming {
+		for _, t := range seen {
+			if !pastOccurrence(t) && sameContent(t.Content, task.Content, false) {
+				dups = append(dups, task.Content)
+				break
+			}
+		}
+		seen = append(seen, task)
+	}
+
+	return dups
+}
+
+// importTasks добавляет задачи в конец списка, выдавая им новые ID
+// Зависимости между добавленными задачами переводятся на новые ID, ссылки на остальные задачи отбрасываются
+// Защита и чужая история изменений сбрасываются, в историю записывается добавление
+// Дубликаты по умолчанию пропускаются, а при warnDuplicates добавляются с предупреждением в warn
+// Задачи с пустым или слишком длинным текстом пропускаются всегда
+// Возвращает количество добавленных задач
+func importTasks(tl *TodoList, incoming []Task, now time.Time, warnDuplicates bool, warn io.Writer) int {
+	if warnDuplicates {
+		for _, content := range duplicateContents(tl, incoming) {
+			fmt.Fprintf(warn, msg("Предупреждение: задача %q уже существует\n"), content)
+		}
+	}
+
+	start := len(tl.Tasks)
+	newIds := make(map[int]int, len(incoming))
+	for _, task := range incoming {
+		oldId := task.Id
+		task.Id = tl.NextId
+		if err := validateContent(task.Content); err != nil {
+			fmt.Fprintf(warn, msg("Задача %q пропущена: %v\n"), task.Content, err)
+			continue
+		}
+
+		if !warnDuplicates {
+			if err := validateTask(tl, task, false); err != nil {
+				fmt.Fprintf(warn, msg("Задача %q пропущена: %v\n"), task.Content, err)
+				continue
+			}
+		}
+
+		if _, seen := newIds[oldId]; !seen {
+			newIds[oldId] = task.Id
+		}
+
+		task.Locked = false
+		task.History = nil
+		recordEvent(&task, eventAdd, now)
+
+		tl.Tasks = append(tl.Tasks, task)
+		tl.NextId++
+	}
+
+	remapBlockers(tl.Tasks[start:], newIds)
+	return len(tl.Tasks) - start
+}
+
+// importJSON читает JSON-массив объектов с полем content (и необязательными done и tags)
+// и добавляет их в список как новые задачи с новыми ID
+// Возвращает количество добавленных задач
+func importJSON(tl *TodoList, r io.Reader, now time.Time, warnDuplicates bool, warn io.Writer) (int, error) {
+	var items []jsonImportItem
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return 0, fmt.Errorf(msg("Ошибка: не верный JSON: %w"), err)
+	}
+
+	tasks := make([]Task, 0, len(items))
+	for _, item := range items {
+		task := Task{
+			Content:   item.Content,
+			CreatedAt: now.Format(timeLayout),
+		}
+		for _, tag := range item.Tags {
+			task.Tags = appendTag(task.Tags, tag)
+		}
+		if item.Done {
+			markDone(&task, now)
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	return importTasks(tl, tasks, now, warnDuplicates, warn), nil
+}
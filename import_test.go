@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDuplicateContents(t *testing.T) {
+	tl := newTestList("Buy milk", "call mom")
+	incoming := []Task{{Content: "buy MILK"}, {Content: "new"}, {Content: "New"}}
+
+	got := duplicateContents(tl, incoming)
+	if want := []string{"buy MILK", "New"}; !slices.Equal(got, want) {
+		t.Errorf("duplicateContents = %q, want %q", got, want)
+	}
+}
+
+func TestImportTasksWarnDuplicates(t *testing.T) {
+	tests := []struct {
+		name          string
+		warn          bool
+		wantImported  int
+		wantWarnCount int
+	}{
+		{"skip by default", false, 1, 0},
+		{"warn and import", true, 2, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("Buy milk")
+			var warn bytes.Buffer
+
+			imported := importTasks(tl, []Task{{Content: "buy milk"}, {Content: "new"}}, testNow, tt.warn, &warn)
+
+			if imported != tt.wantImported || len(tl.Tasks) != 1+tt.wantImported {
+				t.Errorf("imported = %d, tasks = %d", imported, len(tl.Tasks))
+			}
+			if got := strings.Count(warn.String(), "Предупреждение"); got != tt.wantWarnCount {
+				t.Errorf("warnings = %d, want %d: %q", got, tt.wantWarnCount, warn.String())
+			}
+		})
+	}
+}
+
+func TestImportJSONPartialFields(t *testing.T) {
+	tl := newTestList("existing")
+	input := `[
+		{"content": "only content"},
+		{"content": "done task", "done": true},
+		{"content": "tagged", "tags": ["work", " Work ", "home"], "source": "other-tool", "priority": 5},
+		{"done": true},
+		{"content": "EXISTING"}
+	]`
+
+	var warn bytes.Buffer
+	n, err := importJSON(tl, strings.NewReader(input), testNow, false, &warn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 3 {
+		t.Fatalf("imported = %d, want 3; warnings: %s", n, warn.String())
+	}
+	if got := taskIds(tl.Tasks); !slices.Equal(got, []int{1, 2, 3, 4}) || tl.NextId != 5 {
+		t.Errorf("ids = %v, NextId = %d", got, tl.NextId)
+	}
+
+	plain, done, tagged := tl.Tasks[1], tl.Tasks[2], tl.Tasks[3]
+	if plain.Done || plain.CreatedAt != testNow.Format(timeLayout) {
+		t.Errorf("plain task = %+v", plain)
+	}
+	if !done.Done || done.CompletedAt != testNow.Format(timeLayout) {
+		t.Errorf("done task = %+v", done)
+	}
+	if !slices.Equal(tagged.Tags, []string{"work", "home"}) {
+		t.Errorf("tags = %q", tagged.Tags)
+	}
+}
+
+func TestImportJSONMalformed(t *testing.T) {
+	tl := newTestList("existing")
+	for _, input := range []string{`[{"content": "a"`, `{"content": "a"}`, `not json`} {
+		if _, err := importJSON(tl, strings.NewReader(input), testNow, false, io.Discard); err == nil {
+			t.Errorf("importJSON(%q): expected an error", input)
+		}
+	}
+	if len(tl.Tasks) != 1 {
+		t.Errorf("tasks = %v", taskIds(tl.Tasks))
+	}
+}
+
+func TestImportTasksRemapsForeignIds(t *testing.T) {
+	tl := newTestList("a", "b")
+	other := []Task{
+		{Id: 10, Content: "design", Locked: true, History: []Event{{Type: eventToggle, At: "2020-01-01 00:00:00"}}},
+		{Id: 11, Content: "build", BlockedBy: []int{10, 99}},
+		{Id: 12, Content: "A"},
+		{Id: 13, Content: "ship", BlockedBy: []int{11, 12}},
+	}
+
+	if n := importTasks(tl, other, testNow, false, io.Discard); n != 3 {
+		t.Fatalf("imported = %d, want 3", n)
+	}
+
+	if got := taskIds(tl.Tasks); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Fatalf("ids = %v", got)
+	}
+	design, build, ship := tl.Tasks[2], tl.Tasks[3], tl.Tasks[4]
+	if !slices.Equal(build.BlockedBy, []int{3}) {
+		t.Errorf("build blocked by %v, want [3]", build.BlockedBy)
+	}
+	// #12 пропущен как дубликат, поэтому ссылка на него отбрасывается
+	if !slices.Equal(ship.BlockedBy, []int{4}) {
+		t.Errorf("ship blocked by %v, want [4]", ship.BlockedBy)
+	}
+	if design.Locked {
+		t.Error("imported task is still locked")
+	}
+	if len(design.History) != 1 || design.History[0].Type != eventAdd {
+		t.Errorf("history = %+v, want a single add event", design.History)
+	}
+	if !slices.Equal(other[1].BlockedBy, []int{10, 99}) {
+		t.Errorf("source tasks changed: %v", other[1].BlockedBy)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// checkIntegrity проверяет целостность списка задач и возвращает найденные проблемы
+// Проверяются уникальность ID, значение NextId, согласованность статуса и времени завершения,
+// а также корректность всех дат
+func checkIntegrity(tl *TodoList) []string {
+	var problems []string
+	seen := make(map[int]bool)
+	maxId := 0
+	for _, task := range tl.Tasks {
+		if seen[task.Id] {
+			problems = append(problems, fmt.Sprintf(msg("ID %d встречается несколько раз"), task.Id))
+		}
+		seen[task.Id] = true
+
+		if task.Id > maxId {
+			maxId = task.Id
+		}
+
+		if task.Done && task.CompletedAt == "" {
+			problems = append(problems, fmt.Sprintf(msg("задача #%d выполнена, но не имеет времени завершения"), task.Id))
+		}
+
+		if !task.Done && task.CompletedAt != "" {
+			problems = append(problems, fmt.Sprintf(msg("задача #%d не выполнена, но имеет время завершения"), task.Id))
+		}
+
+		if _, err := parseTime(task.CreatedAt); err != nil {
+			problems = append(problems, fmt.Sprintf(msg("задача #%d: не верная дата создания %q"), task.Id, task.CreatedAt))
+		}
+
+		if task.CompletedAt != "" {
+			if _, err := parseTime(task.CompletedAt); err != nil {
+				problems = append(problems, fmt.Sprintf(msg("задача #%d: не верное время завершения %q"), task.Id, task.CompletedAt))
+			}
+		}
+
+		if task.DueDate != "" {
+			if _, err := time.Parse(dateLayout, task.DueDate); err != nil {
+				problems = append(problems, fmt.Sprintf(msg("задача #%d: не верный срок %q"), task.Id, task.DueDate))
+			}
+		}
+
+		for _, e := range task.History {
+			if _, err := parseTime(e.At); err != nil {
+				problems = append(problems, fmt.Sprintf(msg("задача #%d: не верное время события %q"), task.Id, e.At))
+			}
+		}
+	}
+
+	if tl.NextId <= maxId {
+		problems = append(problems, fmt.Sprintf(msg("next_id (%d) должен быть больше максимального ID (%d)"), tl.NextId, maxId))
+	}
+
+	return problems
+}
+
+// emptyTasks возвращает ID задач, текст которых пуст или состоит только из пробелов
+func emptyTasks(tl *TodoList) []int {
+	var ids []int
+	for _, task := range tl.Tasks {
+		if strings.TrimSpace(task.Content) == "" {
+			ids = append(ids, task.Id)
+		}
+	}
+	return ids
+}
+
+// removeEmpty удаляет задачи с пустым текстом и возвращает их количество
+// Защищённые задачи без force остаются, их ID возвращаются отдельно
+func removeEmpty(tl *TodoList, force bool) (int, []int) {
+	before := len(tl.Tasks)
+	var skipped []int
+	tl.Tasks = slices.DeleteFunc(tl.Tasks, func(task Task) bool {
+		if strings.TrimSpace(task.Content) != "" {
+			return false
+		}
+		if protected(task, force) {
+			skipped = append(skipped, task.Id)
+			return false
+		}
+		return true
+	})
+	return before - len(tl.Tasks), skipped
+}
+
+// reassignId меняет ID задачи на позиции pos (начиная с 1) на newId
+// Нужна для исправления повторяющихся ID, поэтому задача выбирается по позиции, а не по ID.
+// Новый ID должен быть положительным и не занятым другой задачей; при необходимости увеличивается NextId
+func reassignId(tl *TodoList, pos, newId int) error {
+	if pos < 1 || pos > len(tl.Tasks) {
+		return fmt.Errorf(msg("Ошибка: нет задачи на позиции %d"), pos)
+	}
+	if newId < 1 {
+		return errors.New(msg("Ошибка: ID должен быть положительным"))
+	}
+
+	for i, task := range tl.Tasks {
+		if i != pos-1 && task.Id == newId {
+			return fmt.Errorf(msg("Ошибка: ID %d уже занят"), newId)
+		}
+	}
+
+	tl.Tasks[pos-1].Id = newId
+	if tl.NextId <= newId {
+		tl.NextId = newId + 1
+	}
+	return nil
+}
+
+// reindex перенумеровывает задачи по порядку списка, начиная с 1, и устанавливает NextId
+// Ссылки на зависимости обновляются, ссылки на отсутствующие задачи удаляются.
+// Возвращает количество задач, у которых изменился ID
+func reindex(tl *TodoList) int {
+	newIds := make(map[int]int, len(tl.Tasks))
+	changed := 0
+	for i := range tl.Tasks {
+		if _, seen := newIds[tl.Tasks[i].Id]; !seen {
+			newIds[tl.Tasks[i].Id] = i + 1
+		}
+		if tl.Tasks[i].Id != i+1 {
+			changed++
+		}
+		tl.Tasks[i].Id = i + 1
+	}
+
+	remapBlockers(tl.Tasks, newIds)
+
+	tl.NextId = len(tl.Tasks) + 1
+	return changed
+}
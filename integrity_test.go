@@ -0,0 +1,110 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCheckIntegrityOK(t *testing.T) {
+	tl := newTestList("a", "b")
+	markDone(&tl.Tasks[1], testNow)
+
+	if problems := checkIntegrity(tl); len(problems) != 0 {
+		t.Errorf("checkIntegrity = %q, want none", problems)
+	}
+}
+
+func TestCheckIntegrityProblems(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(tl *TodoList)
+		want    string
+	}{
+		{"duplicate id", func(tl *TodoList) { tl.Tasks[1].Id = 1 }, "ID 1 встречается несколько раз"},
+		{"next id too small", func(tl *TodoList) { tl.NextId = 2 }, "next_id (2)"},
+		{"done without completion", func(tl *TodoList) { tl.Tasks[0].Done = true }, "выполнена, но не имеет времени завершения"},
+		{"completion without done", func(tl *TodoList) { tl.Tasks[0].CompletedAt = "2024-05-01 10:00:00" }, "не выполнена, но имеет время завершения"},
+		{"bad created at", func(tl *TodoList) { tl.Tasks[0].CreatedAt = "yesterday" }, "не верная дата создания"},
+		{"bad completed at", func(tl *TodoList) {
+			tl.Tasks[0].Done = true
+			tl.Tasks[0].CompletedAt = "soon"
+		}, "не верное время завершения"},
+		{"bad due date", func(tl *TodoList) { tl.Tasks[0].DueDate = "2024-13-01" }, "не верный срок"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("a", "b")
+			tt.corrupt(tl)
+
+			problems := checkIntegrity(tl)
+			if len(problems) != 1 || !strings.Contains(problems[0], tt.want) {
+				t.Errorf("checkIntegrity = %q, want one problem containing %q", problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmptyTasks(t *testing.T) {
+	tl := newTestList("a", "", "  ", "b", "\t\n", " c ")
+
+	if got := emptyTasks(tl); !slices.Equal(got, []int{2, 3, 5}) {
+		t.Errorf("emptyTasks = %v, want [2 3 5]", got)
+	}
+
+	if removed, _ := removeEmpty(tl, false); removed != 3 {
+		t.Errorf("removeEmpty = %d, want 3", removed)
+	}
+	if got := taskIds(tl.Tasks); !slices.Equal(got, []int{1, 4, 6}) {
+		t.Errorf("remaining = %v, want [1 4 6]", got)
+	}
+	if got := emptyTasks(tl); len(got) != 0 {
+		t.Errorf("emptyTasks after removal = %v", got)
+	}
+}
+
+func TestReassignId(t *testing.T) {
+	tl := newTestList("a", "b", "c")
+	tl.Tasks[2].Id = 2 // Дубликат ID, который находит checkIntegrity
+
+	if err := reassignId(tl, 3, 10); err != nil {
+		t.Fatal(err)
+	}
+	if got := taskIds(tl.Tasks); !slices.Equal(got, []int{1, 2, 10}) || tl.NextId != 11 {
+		t.Errorf("ids = %v, NextId = %d", got, tl.NextId)
+	}
+	if problems := checkIntegrity(tl); len(problems) != 0 {
+		t.Errorf("problems after repair: %q", problems)
+	}
+
+	// Свободный ID меньше NextId не меняет NextId
+	if err := reassignId(tl, 1, 5); err != nil || tl.NextId != 11 {
+		t.Errorf("reassignId = %v, NextId = %d", err, tl.NextId)
+	}
+}
+
+func TestReassignIdRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		pos   int
+		newId int
+	}{
+		{"collision", 1, 2},
+		{"position too small", 0, 7},
+		{"position too large", 4, 7},
+		{"non-positive id", 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("a", "b", "c")
+			if err := reassignId(tl, tt.pos, tt.newId); err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := taskIds(tl.Tasks); !slices.Equal(got, []int{1, 2, 3}) || tl.NextId != 4 {
+				t.Errorf("list changed: %v, NextId = %d", got, tl.NextId)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// session хранит список задач интерактивного режима и защищает его от одновременного доступа
+// Список загружается один раз и записывается только после изменений
+type session struct {
+	mu         sync.Mutex
+	tl         *TodoList
+	save       func(*TodoList) error
+	flushEvery int  // Сохранять после каждых flushEvery изменений (0 — только при выходе)
+	pending    int  // Количество несохранённых изменений
+	force      bool // Разрешить изменение и удаление защищённых задач
+}
+
+// exec выполняет одну команду и при необходимости сохраняет изменения
+// Команда и сохранение выполняются в одной критической секции
+func (s *session) exec(args []string, w io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed, err := runCommand(s.tl, args, s.force, w)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		s.pending++
+		if s.flushEvery > 0 && s.pending >= s.flushEvery {
+			return s.flushLocked()
+		}
+	}
+
+	return nil
+}
+
+// flush сохраняет несохранённые изменения
+func (s *session) flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.flushLocked()
+}
+
+// flushLocked сохраняет несохранённые изменения; блокировка должна быть уже захвачена
+func (s *session) flushLocked() error {
+	if s.pending == 0 {
+		return nil
+	}
+
+	if err := s.save(s.tl); err != nil {
+		return err
+	}
+
+	s.pending = 0
+	return nil
+}
+
+// run читает команды построчно из r до quit/exit или конца ввода
+// Перед возвратом сохраняет все несохранённые изменения
+func (s *session) run(r io.Reader, w io.Writer) error {
+	fmt.Fprintln(w, msg("Интерактивный режим. Введите help для списка команд, quit для выхода"))
+	scanner := bufio.NewScanner(r)
+	for {
+		fmt.Fprint(w, "> ")
+		if !scanner.Scan() {
+			break
+		}
+
+		args := strings.Fields(scanner.Text())
+		if len(args) == 1 && (args[0] == "quit" || args[0] == "exit") {
+			break
+		}
+
+		if err := s.exec(args, w); err != nil {
+			fmt.Fprintln(w, err.Error())
+		}
+	}
+
+	return s.flush()
+}
+
+// handleSignals ждёт сигнала, сохраняет список и завершает программу через exit
+// Блокировка гарантирует, что сохранение не начнётся посреди выполнения команды
+func (s *session) handleSignals(sigs <-chan os.Signal, exit func(int)) {
+	<-sigs
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.flushLocked(); err != nil {
+		fmt.Fprintf(os.Stderr, msg("Ошибка сохранения задач: %v\n"), err)
+		exit(1)
+		return
+	}
+
+	exit(0)
+}
+
+// runCommand выполняет одну команду интерактивного режима над списком задач
+// Защищённые задачи переключаются и удаляются только при force. Возвращает true, если список был изменён
+func runCommand(tl *TodoList, args []string, force bool, w io.Writer) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+
+	name, rest := strings.ToLower(args[0]), args[1:]
+	switch name {
+	case "add":
+		task, err := createTask(tl, strings.Join(rest, " "), AddOptions{Creator: resolveCreator("", os.Getenv)}, time.Now())
+		if err != nil {
+			return false, err
+		}
+		fmt.Fprintf(w, msg("Добавлена задача %d: %s\n"), task.Id, task.Content)
+		return true, nil
+
+	case "list", "ls":
+		listTasks(tl.Tasks, w, DisplayOptions{})
+		return false, nil
+
+	case "done":
+		id, err := commandId(rest)
+		if err != nil {
+			return false, err
+		}
+		if err := completeTask(tl, id, force, time.Now()); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(w, msg("Задача #%d отмечена как выполнено\n"), id)
+		return true, nil
+
+	case "toggle":
+		id, err := commandId(rest)
+		if err != nil {
+			return false, err
+		}
+		done, err := toggleStatus(tl, id, force, time.Now())
+		if err != nil {
+			return false, err
+		}
+		status := msg("не выполнено")
+		if done {
+			status = msg("выполнено")
+		}
+		fmt.Fprintf(w, msg("Задача #%d отмечена как %s\n"), id, status)
+		return true, nil
+
+	case "rm", "delete":
+		id, err := commandId(rest)
+		if err != nil {
+			return false, err
+		}
+		if err := removeTask(tl, id, force); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(w, msg("Задача #%d была удалена\n"), id)
+		return true, nil
+
+	case "rename":
+		id, err := commandId(rest)
+		if err != nil {
+			return false, err
+		}
+		content := strings.Join(rest[1:], " ")
+		if err := editTask(tl, id, content, time.Now()); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(w, msg("Задача #%d изменена: %s\n"), id, content)
+		return true, nil
+
+	case "help":
+		fmt.Fprintln(w, msg("Команды: add <текст>, list, done <id>, toggle <id>, rm <id>, rename <id> <текст>, help, quit"))
+		return false, nil
+
+	default:
+		return false, fmt.Errorf(msg("Ошибка: неизвестная команда %q"), args[0])
+	}
+}
+
+// commandId разбирает ID задачи из первого аргумента команды
+func commandId(args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, errors.New(msg("Ошибка: не указан id"))
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, errors.New(msg("Ошибка: не верный id"))
+	}
+
+	return id, nil
+}
+
+// readOnlyCommands содержит команды, которые не изменяют список задач
+var readOnlyCommands = map[string]bool{"list": true, "ls": true, "help": true}
+
+// runSubcommand выполняет команду, переданную позиционными аргументами, например: todo rename 3 "текст"
+// При ошибке программа завершается с ненулевым кодом
+func runSubcommand(tl *TodoList, args []string, force bool) {
+	if !readOnlyCommands[strings.ToLower(args[0])] {
+		requireWritable()
+	}
+
+	changed, err := runCommand(tl, args, force, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	if changed {
+		saveOrExit(tl)
+	}
+}
+
+// runBatch выполняет команды из r построчно над одним списком задач
+// Пустые строки и строки, начинающиеся с #, пропускаются
+// Ошибки выводятся с номером строки, выполнение продолжается со следующей строки
+// Возвращает признак изменения списка и количество строк с ошибками
+func runBatch(tl *TodoList, r io.Reader, force bool, w io.Writer) (bool, int, error) {
+	changed, failed := false, 0
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fmt.Fprintf(w, msg("Строка %d: "), line)
+		ok, err := runCommand(tl, strings.Fields(text), force, w)
+		if err != nil {
+			fmt.Fprintln(w, err.Error())
+			failed++
+			continue
+		}
+		changed = changed || ok
+	}
+
+	return changed, failed, scanner.Err()
+}
+
+// runInteractive запускает интерактивный режим: команды читаются построчно из r
+// Изменения сохраняются после каждых flushEvery команд и при выходе
+// При SIGINT или SIGTERM список сохраняется, и программа завершается с кодом 0
+func runInteractive(tl *TodoList, r io.Reader, w io.Writer, flushEvery int, force bool) error {
+	s := &session{tl: tl, save: saveTask, flushEvery: flushEvery, force: force}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+	go s.handleSignals(sigs, os.Exit)
+
+	return s.run(r, w)
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestHandleSignalsSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	oldPath := tasksPath
+	tasksPath = path
+	defer func() { tasksPath = oldPath }()
+
+	tl := newTestList()
+	s := &session{tl: tl, save: saveTask}
+	if err := s.exec([]string{"add", "unsaved"}, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	// Пока команда держит блокировку, обработчик не должен начинать сохранение
+	s.mu.Lock()
+	sigs := make(chan os.Signal, 1)
+	codes := make(chan int, 1)
+	go s.handleSignals(sigs, func(code int) { codes <- code })
+	sigs <- syscall.SIGINT
+
+	select {
+	case <-codes:
+		t.Fatal("handler exited inside the critical section")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("file written inside the critical section: %v", err)
+	}
+	s.mu.Unlock()
+
+	select {
+	case code := <-codes:
+		if code != 0 {
+			t.Errorf("exit code = %d, want 0", code)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler did not exit")
+	}
+
+	saved, err := loadTodoFile(path)
+	if err != nil {
+		t.Fatalf("loadTodoFile: %v", err)
+	}
+	if len(saved.Tasks) != 1 || saved.Tasks[0].Content != "unsaved" {
+		t.Errorf("saved tasks = %+v", saved.Tasks)
+	}
+}
+
+func TestHandleSignalsSaveError(t *testing.T) {
+	s := &session{tl: newTestList("a"), pending: 1, save: func(*TodoList) error { return errors.New("disk full") }}
+
+	sigs := make(chan os.Signal, 1)
+	sigs <- syscall.SIGTERM
+	code := -1
+	s.handleSignals(sigs, func(c int) { code = c })
+
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+}
+
+func TestRunCommandRename(t *testing.T) {
+	tl := newTestList("old text", "other")
+	tl.Tasks[0].Tags = []string{"work"}
+	tl.Tasks[0].DueDate = "2024-06-10"
+
+	changed, err := runCommand(tl, []string{"rename", "1", "new", "text"}, false, io.Discard)
+	if err != nil || !changed {
+		t.Fatalf("runCommand = %v, %v", changed, err)
+	}
+
+	task := tl.Tasks[0]
+	if task.Content != "new text" || task.DueDate != "2024-06-10" || len(task.Tags) != 1 || task.CreatedAt != newTestList("a", "b").Tasks[0].CreatedAt {
+		t.Errorf("renamed task = %+v", task)
+	}
+}
+
+func TestRunCommandRenameErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"missing id", []string{"rename"}},
+		{"bad id", []string{"rename", "one", "text"}},
+		{"missing text", []string{"rename", "1"}},
+		{"unknown task", []string{"rename", "5", "text"}},
+		{"duplicate", []string{"rename", "1", "OTHER"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("old text", "other")
+			changed, err := runCommand(tl, tt.args, false, io.Discard)
+			if err == nil || changed {
+				t.Fatalf("runCommand = %v, %v; want an error", changed, err)
+			}
+			if tl.Tasks[0].Content != "old text" {
+				t.Errorf("content changed to %q", tl.Tasks[0].Content)
+			}
+		})
+	}
+}
+
+func TestSessionSingleFinalWrite(t *testing.T) {
+	var writes []*TodoList
+	s := &session{tl: newTestList("a"), save: func(tl *TodoList) error {
+		snapshot := *tl
+		snapshot.Tasks = append([]Task(nil), tl.Tasks...)
+		writes = append(writes, &snapshot)
+		return nil
+	}}
+
+	input := "add b\nlist\ndone 1\nrename 2 c\nhelp\nquit\nadd ignored\n"
+	if err := s.run(strings.NewReader(input), io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(writes) != 1 {
+		t.Fatalf("writes = %d, want 1", len(writes))
+	}
+	final := writes[0]
+	if len(final.Tasks) != 2 || !final.Tasks[0].Done || final.Tasks[1].Content != "c" {
+		t.Errorf("final write = %+v", final.Tasks)
+	}
+}
+
+func TestSessionFlushEvery(t *testing.T) {
+	tests := []struct {
+		name       string
+		flushEvery int
+		input      string
+		wantWrites int
+	}{
+		{"read-only session", 0, "list\nhelp\n", 0},
+		{"batched", 2, "add b\nadd c\nadd d\n", 2},
+		{"every command", 1, "add b\nlist\nadd c\n", 2},
+		{"failed command is not a change", 1, "done 9\n", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writes := 0
+			s := &session{tl: newTestList("a"), flushEvery: tt.flushEvery, save: func(*TodoList) error {
+				writes++
+				return nil
+			}}
+
+			if err := s.run(strings.NewReader(tt.input), io.Discard); err != nil {
+				t.Fatal(err)
+			}
+			if writes != tt.wantWrites {
+				t.Errorf("writes = %d, want %d", writes, tt.wantWrites)
+			}
+		})
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "batch.txt")
+	batch := "# setup\nadd Buy milk\nadd Call mom\n\ndone 1\nrm 9\nfrobnicate\nadd Fix roof\nrm 2\n"
+	if err := os.WriteFile(path, []byte(batch), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	tl := newTestList()
+	var out bytes.Buffer
+	changed, failed, err := runBatch(tl, f, false, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !changed || failed != 2 {
+		t.Errorf("changed = %v, failed = %d; want true, 2", changed, failed)
+	}
+	if got := taskIds(tl.Tasks); !slices.Equal(got, []int{1, 3}) || !tl.Tasks[0].Done || tl.Tasks[1].Content != "Fix roof" {
+		t.Errorf("final tasks = %+v", tl.Tasks)
+	}
+	for _, line := range []string{"Строка 6: Задача не найдена", "Строка 7: Ошибка: неизвестная команда"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("output missing %q:\n%s", line, out.String())
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// lastRunPath возвращает путь к файлу с отметкой последнего просмотра рядом с файлом задач
+func lastRunPath(tasksPath string) string {
+	return strings.TrimSuffix(tasksPath, ".json") + ".last-run"
+}
+
+// loadLastRun читает отметку последнего просмотра
+// Если файла нет, возвращается нулевое время
+func loadLastRun(path string) (time.Time, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t, err := parseTime(strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf(msg("не верная отметка в %s: %w"), path, err)
+	}
+	return t, nil
+}
+
+// saveLastRun сохраняет отметку последнего просмотра
+func saveLastRun(path string, now time.Time) error {
+	return writeFileAtomic(path, []byte(now.Format(timeLayout)+"\n"), 0644)
+}
+
+// changedSince возвращает задачи, созданные и выполненные после момента since
+func changedSince(tl *TodoList, since time.Time) (created, completed []Task) {
+	for _, task := range tl.Tasks {
+		if t, err := parseTime(task.CreatedAt); err == nil && t.After(since) {
+			created = append(created, task)
+		}
+		if !task.Done || task.CompletedAt == "" {
+			continue
+		}
+		if t, err := parseTime(task.CompletedAt); err == nil && t.After(since) {
+			completed = append(completed, task)
+		}
+	}
+	return created, completed
+}
+
+// printChangesSince выводит задачи, созданные и выполненные после момента since
+func printChangesSince(tl *TodoList, since time.Time, w io.Writer) {
+	created, completed := changedSince(tl, since)
+	if len(created) == 0 && len(completed) == 0 {
+		fmt.Fprintln(w, msg("Изменений с прошлого просмотра нет"))
+		return
+	}
+
+	if len(created) > 0 {
+		fmt.Fprintln(w, msg("Добавлены:"))
+		for _, task := range created {
+			fmt.Fprintf(w, msg("%d, %s (создана: %s)\n"), task.Id, task.Content, task.CreatedAt)
+		}
+	}
+	if len(completed) > 0 {
+		fmt.Fprintln(w, msg("Выполнены:"))
+		for _, task := range completed {
+			fmt.Fprintf(w, msg("%d, %s (выполнена: %s)\n"), task.Id, task.Content, task.CompletedAt)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestChangedSince(t *testing.T) {
+	since := time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)
+	tl := &TodoList{Tasks: []Task{
+		{Id: 1, CreatedAt: "2024-05-01 10:00:00"},
+		{Id: 2, CreatedAt: "2024-06-01 12:30:00"},
+		{Id: 3, CreatedAt: "2024-05-01 10:00:00", Done: true, CompletedAt: "2024-06-01 13:00:00"},
+		{Id: 4, CreatedAt: "2024-06-02 09:00:00", Done: true, CompletedAt: "2024-06-02 10:00:00"},
+		{Id: 5, CreatedAt: "2024-05-01 10:00:00", Done: true, CompletedAt: "2024-06-01 11:59:59"},
+		{Id: 6, CreatedAt: "2024-06-01 12:00:00"},
+	}}
+
+	created, completed := changedSince(tl, since)
+
+	if got := taskIds(created); !slices.Equal(got, []int{2, 4}) {
+		t.Errorf("created = %v, want [2 4]", got)
+	}
+	if got := taskIds(completed); !slices.Equal(got, []int{3, 4}) {
+		t.Errorf("completed = %v, want [3 4]", got)
+	}
+}
+
+func TestLastRunMarker(t *testing.T) {
+	path := lastRunPath(filepath.Join(t.TempDir(), "tasks.json"))
+
+	since, err := loadLastRun(path)
+	if err != nil || !since.IsZero() {
+		t.Fatalf("loadLastRun without a marker = %v, %v", since, err)
+	}
+
+	if err := saveLastRun(path, testNow); err != nil {
+		t.Fatal(err)
+	}
+	since, err = loadLastRun(path)
+	if err != nil || !since.Equal(testNow) {
+		t.Errorf("loadLastRun = %v, %v, want %v", since, err, testNow)
+	}
+
+	if err := os.WriteFile(path, []byte("garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadLastRun(path); err == nil {
+		t.Error("expected an error for a corrupt marker")
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// setLocked защищает задачу от удаления и изменения статуса или снимает защиту
+func setLocked(tl *TodoList, id int, locked bool) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	tl.Tasks[index].Locked = locked
+	return nil
+}
+
+// checkLocked возвращает ошибку, если задача защищена и force не задан
+func checkLocked(tl *TodoList, id int, force bool) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 || !protected(tl.Tasks[index], force) {
+		return nil
+	}
+
+	return fmt.Errorf(msg("Ошибка: задача #%d защищена, используйте --unlock или --force"), id)
+}
+
+// protected сообщает, что задачу нельзя удалять и менять её статус: она защищена и force не задан
+// Массовые команды пропускают такие задачи и возвращают их ID
+func protected(task Task, force bool) bool {
+	return task.Locked && !force
+}
+
+// printLockedSkipped выводит защищённые задачи, пропущенные массовой командой
+func printLockedSkipped(ids []int) {
+	for _, id := range ids {
+		fmt.Printf(msg("Задача #%d пропущена: защищена\n"), id)
+	}
+}
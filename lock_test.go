@@ -0,0 +1,202 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// newLockedList создаёт список, в котором задача #1 защищена
+func newLockedList() *TodoList {
+	tl := newTestList("reference", "regular")
+	tl.Tasks[0].Locked = true
+	return tl
+}
+
+func TestLockedTaskDeleteRequiresForce(t *testing.T) {
+	tests := []struct {
+		name  string
+		op    func(tl *TodoList, force bool) error
+		check func(tl *TodoList) bool
+	}{
+		{"removeTask", func(tl *TodoList, force bool) error { return removeTask(tl, 1, force) }, removed},
+		{"rm command", func(tl *TodoList, force bool) error {
+			_, err := runCommand(tl, []string{"rm", "1"}, force, io.Discard)
+			return err
+		}, removed},
+		{"batch", func(tl *TodoList, force bool) error {
+			if _, failed, err := runBatch(tl, strings.NewReader("delete 1\n"), force, io.Discard); err != nil || failed > 0 {
+				return errors.New("batch line failed")
+			}
+			return nil
+		}, removed},
+		{"toggleStatus", func(tl *TodoList, force bool) error {
+			_, err := toggleStatus(tl, 1, force, testNow)
+			return err
+		}, toggled},
+		{"toggle command", func(tl *TodoList, force bool) error {
+			_, err := runCommand(tl, []string{"toggle", "1"}, force, io.Discard)
+			return err
+		}, toggled},
+		{"toggleByContent", func(tl *TodoList, force bool) error {
+			_, _, err := toggleByContent(tl, "REFERENCE", force, testNow)
+			return err
+		}, toggled},
+		{"completeTask", func(tl *TodoList, force bool) error { return completeTask(tl, 1, force, testNow) }, toggled},
+		{"done command", func(tl *TodoList, force bool) error {
+			_, err := runCommand(tl, []string{"done", "1"}, force, io.Discard)
+			return err
+		}, toggled},
+		{"completeWithNote", func(tl *TodoList, force bool) error {
+			return completeWithNote(tl, 1, "note", force, testNow)
+		}, toggled},
+		{"completeSilent", func(tl *TodoList, force bool) error {
+			_, _, err := completeSilent(tl, "1", force, testNow)
+			return err
+		}, toggled},
+		{"completeAndAdd", func(tl *TodoList, force bool) error {
+			_, err := completeAndAdd(tl, 1, "next", AddOptions{}, force, testNow)
+			return err
+		}, toggled},
+		{"setCompletedAt", func(tl *TodoList, force bool) error { return setCompletedAt(tl, 1, force, testNow) }, toggled},
+		{"clearAllTasks", func(tl *TodoList, force bool) error { return clearAllTasks(tl, force) }, removed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newLockedList()
+			if err := tt.op(tl, false); err == nil {
+				t.Error("locked task changed without force")
+			}
+			if tt.check(tl) {
+				t.Errorf("locked task changed: %+v", tl.Tasks)
+			}
+
+			if err := tt.op(tl, true); err != nil {
+				t.Fatalf("with force: %v", err)
+			}
+			if !tt.check(tl) {
+				t.Errorf("locked task not changed with force: %+v", tl.Tasks)
+			}
+		})
+	}
+}
+
+func TestBulkCommandsSkipLocked(t *testing.T) {
+	past := testNow.AddDate(0, 0, -1).Format(dateLayout)
+	tests := []struct {
+		name    string
+		prepare func(tl *TodoList)
+		op      func(tl *TodoList, force bool) []int
+		check   func(tl *TodoList) bool
+	}{
+		{"completeAllTasks", nil, func(tl *TodoList, force bool) []int {
+			_, skipped := completeAllTasks(tl, func(Task) bool { return true }, force, testNow)
+			return skipped
+		}, toggled},
+		{"completeOldest", nil, func(tl *TodoList, force bool) []int {
+			completeOldest(tl, 2, force, testNow)
+			return nil
+		}, toggled},
+		{"completeLastTask", func(tl *TodoList) { tl.Tasks = tl.Tasks[:1] }, func(tl *TodoList, force bool) []int {
+			completeLastTask(tl, force, testNow)
+			return nil
+		}, toggled},
+		{"invertAll", nil, func(tl *TodoList, force bool) []int {
+			_, _, skipped := invertAll(tl, force, testNow)
+			return skipped
+		}, toggled},
+		{"syncParentCompletion", func(tl *TodoList) {
+			tl.Tasks[0].Subtasks = []Subtask{{Content: "step", Done: true}}
+		}, func(tl *TodoList, force bool) []int {
+			_, skipped := syncParentCompletion(tl, force, testNow)
+			return skipped
+		}, toggled},
+		{"autoCloseOverdue", func(tl *TodoList) {
+			tl.Tasks[0].Tags, tl.Tasks[0].DueDate = []string{"auto"}, past
+		}, func(tl *TodoList, force bool) []int {
+			_, skipped := autoCloseOverdue(tl, "auto", force, testNow)
+			return skipped
+		}, toggled},
+		{"deleteByTag", func(tl *TodoList) {
+			tl.Tasks[0].Tags, tl.Tasks[1].Tags = []string{"old"}, []string{"old"}
+		}, func(tl *TodoList, force bool) []int {
+			_, skipped := deleteByTag(tl, "old", force)
+			return skipped
+		}, removed},
+		{"removeEmpty", func(tl *TodoList) {
+			tl.Tasks[0].Content, tl.Tasks[1].Content = " ", ""
+		}, func(tl *TodoList, force bool) []int {
+			_, skipped := removeEmpty(tl, force)
+			return skipped
+		}, removed},
+		{"dedupe", func(tl *TodoList) {
+			// Защищённая задача новее дубликата, поэтому при слиянии удаляется именно она
+			tl.Tasks[1].Content = "REFERENCE"
+			tl.Tasks[0].CreatedAt = testNow.Format(timeLayout)
+		}, func(tl *TodoList, force bool) []int {
+			_, skipped := dedupe(tl, force)
+			return skipped
+		}, removed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newLockedList()
+			if tt.prepare != nil {
+				tt.prepare(tl)
+			}
+
+			skipped := tt.op(tl, false)
+			if tt.check(tl) {
+				t.Errorf("locked task changed without force: %+v", tl.Tasks)
+			}
+			if skipped != nil && !slices.Equal(skipped, []int{1}) {
+				t.Errorf("skipped = %v, want [1]", skipped)
+			}
+
+			if skipped := tt.op(tl, true); len(skipped) != 0 {
+				t.Errorf("skipped with force = %v", skipped)
+			}
+			if !tt.check(tl) {
+				t.Errorf("locked task not changed with force: %+v", tl.Tasks)
+			}
+		})
+	}
+}
+
+func TestUnlockedTaskNeedsNoForce(t *testing.T) {
+	tl := newLockedList()
+	if err := removeTask(tl, 2, false); err != nil {
+		t.Errorf("removeTask for an unlocked task: %v", err)
+	}
+
+	if err := setLocked(tl, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := toggleStatus(tl, 1, false, testNow); err != nil {
+		t.Errorf("toggleStatus after unlock: %v", err)
+	}
+}
+
+func TestCheckLockedMessage(t *testing.T) {
+	want := "Ошибка: задача #1 защищена, используйте --unlock или --force"
+	if err := checkLocked(newLockedList(), 1, false); err == nil || err.Error() != want {
+		t.Errorf("checkLocked error = %v, want %q", err, want)
+	}
+	if err := checkLocked(newLockedList(), 3, false); err != nil {
+		t.Errorf("checkLocked for a missing task: %v", err)
+	}
+}
+
+// removed проверяет, что задача #1 удалена из списка
+func removed(tl *TodoList) bool {
+	return findTaskIndex(tl, 1) == -1
+}
+
+// toggled проверяет, что задача #1 отмечена выполненной
+func toggled(tl *TodoList) bool {
+	return tl.Tasks[findTaskIndex(tl, 1)].Done
+}
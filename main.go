@@ -2,9 +2,14 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"math/rand"
 	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -12,31 +17,66 @@ import (
 
 // Task представляет собой отдельную задачу
 type Task struct {
-	Id          int    `json:"id"`                     // Уникальный идентификатор задачи
-	Content     string `json:"content"`                // Текст задачи
-	Done        bool   `json:"done"`                   // Статус выполнения
-	CreatedAt   string `json:"created_at"`             // Дата и время создания
-	CompletedAt string `json:"completed_at,omitempty"` // Дата и время завершения (если выполнена)
+	Id          int       `json:"id"`                     // Уникальный идентификатор задачи
+	Content     string    `json:"content"`                // Текст задачи
+	Done        bool      `json:"done"`                   // Статус выполнения
+	CreatedAt   string    `json:"created_at"`             // Дата и время создания
+	CompletedAt string    `json:"completed_at,omitempty"` // Дата и время завершения (если выполнена)
+	Tags        []string  `json:"tags,omitempty"`         // Теги задачи
+	DueDate     string    `json:"due_date,omitempty"`     // Срок выполнения (если задан)
+	Subtasks    []Subtask `json:"subtasks,omitempty"`     // Чек-лист подзадач
+	CreatedBy   string    `json:"created_by,omitempty"`   // Пользователь, создавший задачу
+	Assignee    string    `json:"assignee,omitempty"`     // Исполнитель задачи
+	History     []Event   `json:"history,omitempty"`      // История изменений задачи
+	Notes       string    `json:"notes,omitempty"`        // Заметки к задаче
+	Color       string    `json:"color,omitempty"`        // Цветовая метка задачи
+	BlockedBy   []int     `json:"blocked_by,omitempty"`   // ID задач, которые нужно выполнить раньше
+	Priority    string    `json:"priority,omitempty"`     // Приоритет: high, medium или low
+	Estimate    float64   `json:"estimate,omitempty"`     // Оценка трудоёмкости в часах
+	Recur       string    `json:"recur,omitempty"`        // Период повторения: daily, weekly или monthly
+	RecurUntil  string    `json:"recur_until,omitempty"`  // Дата, после которой задача больше не повторяется
+	Locked      bool      `json:"locked,omitempty"`       // Задача защищена от удаления и изменения статуса
+	Recurred    bool      `json:"recurred,omitempty"`     // Следующее повторение задачи уже создано
 }
 
 // TodoList содержит список всех задач и информацию о следующем доступном ID
 type TodoList struct {
-	Tasks  []Task `json:"tasks"`   // Список задач
-	NextId int    `json:"next_id"` // Следующий доступный ID для новой задачи
+	Tasks  []Task `json:"tasks"`            // Список задач
+	NextId int    `json:"next_id"`          // Следующий доступный ID для новой задачи
+	Frozen bool   `json:"frozen,omitempty"` // Список заморожен: изменения сохраняются только с --force
 }
 
-const maxTaskLength = 200      // Максимальная длина текста задачи в символах
-const tasksPath = "tasks.json" // Путь к файлу для хранения задач
+const maxTaskLength = 200                // Максимальная длина текста задачи в символах
+const timeLayout = "2006-01-02 15:04:05" // Формат хранения даты и времени
+
+var tasksPath = profilePath(".", defaultProfile) // Путь к файлу для хранения задач
+var maxTasks = 0                                 // Максимальное количество задач при сохранении (0 — без ограничения)
+var allowFrozen = false                          // Разрешить сохранение замороженного списка
+var loadedFrozen = false                         // Загруженный список заморожен
+var compactIds = false                           // Перенумеровывать задачи подряд при загрузке
 
 // loadTasks загружает список задач из файла
 // Если файл не существует, создается новый пустой список
+// При compactIds пропуски в ID закрываются так же, как в --reindex
 func loadTasks() (*TodoList, error) {
-	data, err := os.ReadFile(tasksPath)
+	tl, err := loadTodoFile(tasksPath)
+	if os.IsNotExist(err) {
+		return &TodoList{NextId: 1}, nil
+	}
 	if err != nil {
-		if os.IsNotExist(err) {
-			return &TodoList{NextId: 1}, nil
-		}
+		return nil, err
+	}
+
+	if compactIds {
+		reindex(tl)
+	}
+	return tl, nil
+}
 
+// loadTodoFile загружает список задач из указанного файла
+func loadTodoFile(path string) (*TodoList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
 		return nil, err
 	}
 
@@ -49,20 +89,122 @@ func loadTasks() (*TodoList, error) {
 }
 
 // saveTask сохраняет текущий список задач в файл
+// Данные сначала пишутся во временный файл, который затем атомарно заменяет основной
 func saveTask(tl *TodoList) error {
-	data, err := json.MarshalIndent(tl, "", "  ")
+	if tl.Frozen && !allowFrozen {
+		return errors.New(msg("список задач заморожен, используйте --unfreeze или --force"))
+	}
+
+	if err := checkCapacity(tl, 0); err != nil {
+		return err
+	}
+
+	data, err := encodeJSON(tl, false)
+	if err != nil {
+		return err
+	}
+
+	if err := checkWritable(tasksPath); err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(tasksPath, data, 0644); err != nil {
+		return fmt.Errorf(msg("не удалось записать %s: %w"), tasksPath, err)
+	}
+
+	return nil
+}
+
+// writeFileAtomic записывает данные во временный файл рядом с path и переименовывает его в path
+// Так при сбое во время записи исходный файл остаётся целым
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
 	if err != nil {
 		return err
 	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
 
-	return os.WriteFile(tasksPath, data, 0644)
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// checkWritable проверяет, что файл задач можно записать
+// Если файла ещё нет, проверяется возможность создать файл в его директории
+func checkWritable(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err == nil {
+		return f.Close()
+	}
+
+	if !os.IsNotExist(err) {
+		return fmt.Errorf(msg("не удалось записать %s: %w"), path, errors.Unwrap(err))
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".todo-*")
+	if err != nil {
+		return fmt.Errorf(msg("не удалось создать %s: %w"), path, errors.Unwrap(err))
+	}
+
+	tmp.Close()
+	return os.Remove(tmp.Name())
+}
+
+// requireWritable завершает программу с ошибкой, если файл задач недоступен для записи
+// Вызывается до изменения списка, чтобы не сообщать об изменениях, которые нельзя сохранить
+// Для замороженного списка без --force команда отклоняется до изменений
+func requireWritable() {
+	if loadedFrozen && !allowFrozen {
+		fmt.Fprintln(os.Stderr, msg("Ошибка: список задач заморожен, используйте --unfreeze или --force"))
+		os.Exit(1)
+	}
+	if err := checkWritable(tasksPath); err != nil {
+		fmt.Fprintf(os.Stderr, msg("Ошибка: %v\n"), err)
+		os.Exit(1)
+	}
+}
+
+// checkCapacity проверяет, что после добавления added задач список не превысит ограничение maxTasks
+func checkCapacity(tl *TodoList, added int) error {
+	if total := len(tl.Tasks) + added; maxTasks > 0 && total > maxTasks {
+		return fmt.Errorf(msg("в списке %d задач, что больше ограничения %d"), total, maxTasks)
+	}
+	return nil
+}
+
+// requireCapacity завершает программу с ошибкой, если добавление added задач превысит ограничение maxTasks
+// Как и requireWritable, вызывается до изменения списка и вывода сообщений о нём
+func requireCapacity(tl *TodoList, added int) {
+	if err := checkCapacity(tl, added); err != nil {
+		fmt.Fprintf(os.Stderr, msg("Ошибка: %v\n"), err)
+		os.Exit(1)
+	}
+}
+
+// saveOrExit сохраняет список задач и завершает программу с ошибкой, если это не удалось
+func saveOrExit(tl *TodoList) {
+	if err := saveTask(tl); err != nil {
+		fmt.Fprintf(os.Stderr, msg("Ошибка сохранения задач: %v\n"), err)
+		os.Exit(1)
+	}
 }
 
 // parseTaskId преобразует строковый ID в числовой и проверяет его корректность
 func parseTaskId(strId string) (int, bool) {
 	id, err := strconv.Atoi(strId)
 	if err != nil {
-		fmt.Println("Ошибка: не верный id")
+		fmt.Println(msg("Ошибка: не верный id"))
 		return 0, false
 	}
 
@@ -81,195 +223,2326 @@ func findTaskIndex(tl *TodoList, id int) int {
 	return -1
 }
 
-// validateTask проверяет корректность задачи перед добавлением или редактированием
-func validateTask(tl *TodoList, task Task) error {
-	if len(task.Content) > maxTaskLength {
-		return fmt.Errorf("Ошибка: текст задачи не должен превышать %d символов\n", maxTaskLength)
+// findTaskByContent находит индексы задач с указанным текстом (без учета регистра)
+// Прошлые повторения повторяющихся задач не учитываются, чтобы текст указывал на текущее
+func findTaskByContent(tl *TodoList, content string) []int {
+	var indexes []int
+	for i := range tl.Tasks {
+		if !pastOccurrence(tl.Tasks[i]) && strings.EqualFold(tl.Tasks[i].Content, content) {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes
+}
+
+// printFoundIds выводит в w ID задач с указанным текстом, по одному в строке
+// Если таких задач нет, возвращает ошибку
+func printFoundIds(tl *TodoList, content string, w io.Writer) error {
+	indexes := findTaskByContent(tl, content)
+	if len(indexes) == 0 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	for _, i := range indexes {
+		fmt.Fprintln(w, tl.Tasks[i].Id)
+	}
+	return nil
+}
+
+// validateContent проверяет длину текста задачи и то, что он не пустой
+func validateContent(content string) error {
+	if len(content) > maxTaskLength {
+		return fmt.Errorf(msg("Ошибка: текст задачи не должен превышать %d символов\n"), maxTaskLength)
+	}
+
+	if strings.TrimSpace(content) == "" {
+		return errors.New(msg("Ошибка: новый текст задачи не может быть пустым"))
 	}
 
-	if strings.TrimSpace(task.Content) == "" {
-		return fmt.Errorf("Ошибка: новый текст задачи не может быть пустым")
+	return nil
+}
+
+// validateTask проверяет корректность задачи перед добавлением или редактированием
+// При caseSensitive дубликаты ищутся с учетом регистра, иначе без него
+// Прошлые повторения повторяющихся задач дубликатами не считаются
+func validateTask(tl *TodoList, task Task, caseSensitive bool) error {
+	if err := validateContent(task.Content); err != nil {
+		return err
 	}
 
 	for _, t := range tl.Tasks {
-		if strings.EqualFold(t.Content, task.Content) {
-			return fmt.Errorf("Ошибка: задача с таким заголовком уже существует")
+		if t.Id != task.Id && !pastOccurrence(t) && sameContent(t.Content, task.Content, caseSensitive) {
+			return errors.New(msg("Ошибка: задача с таким заголовком уже существует"))
 		}
 	}
 
 	return nil
 }
 
-// listTasks выводит список всех задач с их статусами
-func listTasks(tl *TodoList) {
-	if len(tl.Tasks) == 0 {
-		fmt.Println("Список задач пуст")
+// sameContent сравнивает тексты задач с учетом или без учета регистра
+func sameContent(a, b string, caseSensitive bool) bool {
+	if caseSensitive {
+		return a == b
+	}
+
+	return strings.EqualFold(a, b)
+}
+
+// DisplayOptions содержит настройки отображения задач в списке
+type DisplayOptions struct {
+	Truncate int  // Максимальная длина текста задачи в символах (0 — без ограничения)
+	Color    bool // Выводить цветные метки с помощью ANSI-последовательностей
+
+	TimeFormat string // Формат вывода даты и времени (пустой — формат хранения)
+}
+
+// listTasks выводит список задач с их статусами
+func listTasks(tasks []Task, w io.Writer, opts DisplayOptions) {
+	if len(tasks) == 0 {
+		fmt.Fprintln(w, msg("Список задач пуст"))
 		return
 	}
 
-	fmt.Println("Список задач:")
-	for _, task := range tl.Tasks {
-		status := " "
-		if task.Done {
-			status = "x"
-		}
+	fmt.Fprintln(w, msg("Список задач:"))
+	for _, task := range tasks {
+		fmt.Fprintln(w, formatTaskLine(task, opts))
+	}
+}
 
-		fmt.Printf("%d [%s], %s (создана: %s)", task.Id, status, task.Content, task.CreatedAt)
-		if task.Done && task.CompletedAt != "" {
-			fmt.Printf(", выполнена: %s", task.CompletedAt)
-		}
+// formatTaskLine возвращает строку списка задач для одной задачи
+func formatTaskLine(task Task, opts DisplayOptions) string {
+	status := " "
+	if task.Done {
+		status = "x"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d [%s], %s%s", task.Id, status, colorMarker(task.Color, opts.Color), truncateRunes(task.Content, opts.Truncate))
+	for _, tag := range task.Tags {
+		fmt.Fprintf(&b, " #%s", tag)
+	}
+
+	fmt.Fprintf(&b, msg(" (создана: %s)"), displayTime(task.CreatedAt, opts.TimeFormat))
+	if task.DueDate != "" {
+		fmt.Fprintf(&b, msg(", срок: %s"), task.DueDate)
+	}
+	if task.Priority != "" {
+		fmt.Fprintf(&b, msg(", приоритет: %s"), task.Priority)
+	}
+	if task.Done && task.CompletedAt != "" {
+		fmt.Fprintf(&b, msg(", выполнена: %s"), displayTime(task.CompletedAt, opts.TimeFormat))
+	}
+
+	return b.String()
+}
 
-		fmt.Println()
+// truncateRunes обрезает строку до n символов, заменяя конец многоточием
+// Строка обрезается по символам, а не байтам; при n <= 0 возвращается без изменений
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if n <= 0 || len(runes) <= n {
+		return s
 	}
+
+	return string(runes[:n-1]) + "…"
 }
 
-// addTask добавляет новую задачу в список
-func addTask(tl *TodoList, content string) {
+// AddOptions содержит дополнительные параметры новой задачи
+type AddOptions struct {
+	Tags     []string // Теги задачи
+	DueDate  string   // Срок выполнения в формате 2006-01-02
+	Creator  string   // Пользователь, создающий задачу
+	Assignee string   // Исполнитель задачи
+	Notes    string   // Заметки к задаче
+	Color    string   // Цветовая метка задачи
+	Priority string   // Приоритет задачи
+	Estimate float64  // Оценка трудоёмкости в часах
+	Position int      // Позиция в списке, начиная с 1 (0 — в конец списка)
+
+	CaseSensitiveDupes bool // Искать дубликаты с учетом регистра
+	ValidateOnly       bool // Только проверить задачу, не добавляя её в список
+}
+
+// resolveCreator определяет автора новой задачи
+// Явно указанное имя имеет приоритет, иначе берётся переменная окружения USER
+func resolveCreator(as string, getenv func(string) string) string {
+	if as = strings.TrimSpace(as); as != "" {
+		return as
+	}
+
+	return getenv("USER")
+}
+
+// createTask создаёт новую задачу, проверяет её и вставляет в список
+// Срок, указанный в тексте словом due:<дата> или @<дата>, переносится в DueDate,
+// если он не задан явно
+func createTask(tl *TodoList, content string, opts AddOptions, now time.Time) (Task, error) {
+	task := buildTask(tl, content, opts, now)
+	if err := validateTask(tl, task, opts.CaseSensitiveDupes); err != nil {
+		return Task{}, err
+	}
+	if opts.ValidateOnly {
+		return task, nil
+	}
+
+	recordEvent(&task, eventAdd, now)
+
+	tl.Tasks = insertTask(tl.Tasks, task, opts.Position)
+	tl.NextId++
+	return task, nil
+}
+
+// buildTask собирает новую задачу из текста и параметров, не проверяя и не добавляя её
+// Токен срока в тексте переносится в DueDate
+func buildTask(tl *TodoList, content string, opts AddOptions, now time.Time) Task {
+	if cleaned, due, ok := extractDueToken(content, now); ok {
+		content = cleaned
+		if opts.DueDate == "" {
+			opts.DueDate = due.Format(dateLayout)
+		}
+	}
+
 	task := Task{
 		Id:        tl.NextId,
 		Content:   content,
 		Done:      false,
-		CreatedAt: time.Now().Format("2006-01-02 15:04:05"),
+		CreatedAt: now.Format(timeLayout),
+		Tags:      opts.Tags,
+		DueDate:   opts.DueDate,
+		CreatedBy: opts.Creator,
+		Assignee:  strings.TrimSpace(opts.Assignee),
+		Notes:     strings.TrimSpace(opts.Notes),
+		Color:     opts.Color,
+		Priority:  opts.Priority,
+		Estimate:  opts.Estimate,
+	}
+	return task
+}
+
+// insertTask вставляет задачу на указанную позицию (начиная с 1)
+// 0 означает конец списка, позиции вне диапазона ограничиваются его границами
+func insertTask(tasks []Task, task Task, pos int) []Task {
+	if pos == 0 || pos > len(tasks) {
+		return append(tasks, task)
+	}
+
+	if pos < 1 {
+		pos = 1
 	}
 
-	err := validateTask(tl, task)
+	index := pos - 1
+	tasks = append(tasks, Task{})
+	copy(tasks[index+1:], tasks[index:])
+	tasks[index] = task
+	return tasks
+}
+
+// addTask добавляет новую задачу в список
+func addTask(tl *TodoList, content string, opts AddOptions) {
+	task, err := createTask(tl, content, opts, time.Now())
 	if err != nil {
 		fmt.Println(err.Error())
 		return
 	}
 
-	tl.Tasks = append(tl.Tasks, task)
-	tl.NextId++
-	fmt.Printf("Добавлена задача %d: %s\n", task.Id, content)
+	fmt.Printf(msg("Добавлена задача %d: %s\n"), task.Id, task.Content)
 }
 
-// toggleTask изменяет статус выполнения задачи (выполнено/не выполнено)
-func toggleTask(tl *TodoList, strId string) {
-	id, ok := parseTaskId(strId)
-	if !ok {
-		return
+// setDueDate устанавливает или меняет срок выполнения задачи
+// Пустое значение или none удаляет срок
+func setDueDate(tl *TodoList, id int, due string, now time.Time) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	due = strings.TrimSpace(due)
+	if due == "" || strings.EqualFold(due, "none") {
+		tl.Tasks[index].DueDate = ""
+		recordEvent(&tl.Tasks[index], eventEdit, now)
+		return nil
+	}
+
+	t, err := parseDate(due, now)
+	if err != nil {
+		return err
 	}
 
+	tl.Tasks[index].DueDate = t.Format(dateLayout)
+	recordEvent(&tl.Tasks[index], eventEdit, now)
+	return nil
+}
+
+// bumpTask обновляет время создания задачи на текущее, не меняя остальные поля
+func bumpTask(tl *TodoList, id int, now time.Time) error {
 	index := findTaskIndex(tl, id)
 	if index == -1 {
-		fmt.Println("Задача не найдена")
-		return
+		return errors.New(msg("Задача не найдена"))
 	}
 
-	tl.Tasks[index].Done = !tl.Tasks[index].Done
-	tl.Tasks[index].CompletedAt = ""
-	status := "не выполнено"
-	if tl.Tasks[index].Done {
-		status = "выполнено"
-		tl.Tasks[index].CompletedAt = time.Now().Format("2006-01-02 15:04:05")
+	tl.Tasks[index].CreatedAt = now.Format(timeLayout)
+	recordEvent(&tl.Tasks[index], eventEdit, now)
+	return nil
+}
+
+// editTask заменяет текст задачи, сохраняя остальные поля
+func editTask(tl *TodoList, id int, content string, now time.Time) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
 	}
 
-	fmt.Printf("Задача #%d отмечена как %s\n", id, status)
+	task := tl.Tasks[index]
+	task.Content = content
+	if err := validateTask(tl, task, false); err != nil {
+		return err
+	}
+
+	tl.Tasks[index].Content = content
+	recordEvent(&tl.Tasks[index], eventEdit, now)
+	return nil
 }
 
-// deleteTask удаляет задачу из списка по её ID
-func deleteTask(tl *TodoList, strId string) {
-	id, ok := parseTaskId(strId)
-	if !ok {
-		return
+// appendSeparator — разделитель между текстом задачи и дописываемым текстом
+const appendSeparator = " "
+
+// appendContent дописывает текст в конец текста задачи через appendSeparator
+// Результат проходит те же проверки, что и при редактировании; при ошибке задача не меняется
+func appendContent(tl *TodoList, id int, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return errors.New(msg("Ошибка: нечего дописывать"))
 	}
 
 	index := findTaskIndex(tl, id)
 	if index == -1 {
-		fmt.Println("Задача не найдена")
-		return
+		return errors.New(msg("Задача не найдена"))
 	}
 
-	tl.Tasks = append(tl.Tasks[:index], tl.Tasks[index+1:]...)
-	fmt.Printf("Задача #%d была удалена\n", id)
+	return editTask(tl, id, tl.Tasks[index].Content+appendSeparator+text, time.Now())
 }
 
-// clearAllTasks удаляет все задачи и сбрасывает счётчик ID
-func clearAllTasks(tl *TodoList) {
-	tl.Tasks = []Task{}
-	tl.NextId = 1
-	fmt.Println("Все задачи очищены")
+// toggleStatus изменяет статус выполнения задачи с указанным ID
+// Защищённая задача изменяется только при force. Возвращает новый статус задачи
+func toggleStatus(tl *TodoList, id int, force bool, now time.Time) (bool, error) {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return false, errors.New(msg("Задача не найдена"))
+	}
+
+	if err := checkLocked(tl, id, force); err != nil {
+		return false, err
+	}
+
+	task := &tl.Tasks[index]
+	if !task.Done {
+		finishTask(tl, index, eventToggle, now)
+		return true, nil
+	}
+
+	markPending(task)
+	recordEvent(task, eventToggle, now)
+	return false, nil
 }
 
-// completeAllTasks отмечает все задачи как выполненные
-func completeAllTasks(tl *TodoList) {
-	currentTime := time.Now().Format("2006-01-02 15:04:05")
-	for i := range tl.Tasks {
-		if !tl.Tasks[i].Done {
-			tl.Tasks[i].Done = true
-			tl.Tasks[i].CompletedAt = currentTime
+// toggleTask изменяет статус выполнения задачи (выполнено/не выполнено)
+// Возвращает ID и новый статус; при ошибке список не изменяется
+func toggleTask(tl *TodoList, strId string, force bool) (int, bool, error) {
+	id, err := strconv.Atoi(strId)
+	if err != nil {
+		return 0, false, errors.New(msg("Ошибка: не верный id"))
+	}
+
+	done, err := toggleStatus(tl, id, force, time.Now())
+	if err != nil {
+		return 0, false, err
+	}
+
+	status := msg("не выполнено")
+	if done {
+		status = msg("выполнено")
+	}
+
+	fmt.Printf(msg("Задача #%d отмечена как %s\n"), id, status)
+	return id, done, nil
+}
+
+// toggleByContent изменяет статус единственной задачи с указанным текстом (без учета регистра)
+// Если подходящих задач нет или их несколько, возвращает ошибку со списком ID. Возвращает ID и новый статус
+func toggleByContent(tl *TodoList, content string, force bool, now time.Time) (int, bool, error) {
+	indexes := findTaskByContent(tl, strings.TrimSpace(content))
+	switch len(indexes) {
+	case 0:
+		return 0, false, errors.New(msg("Задача не найдена"))
+	case 1:
+	default:
+		ids := make([]int, len(indexes))
+		for i, index := range indexes {
+			ids[i] = tl.Tasks[index].Id
 		}
+		return 0, false, fmt.Errorf(msg("Ошибка: текст совпадает с несколькими задачами: %s"), formatIds(ids))
 	}
 
-	fmt.Println("Все задачи отмечены как выполненные")
+	id := tl.Tasks[indexes[0]].Id
+	done, err := toggleStatus(tl, id, force, now)
+	return id, done, err
 }
 
-func main() {
-	listFlag := flag.Bool("list", false, "List all tasks")
-	addFlag := flag.String("add", "", "Add a new task")
-	toggleFlag := flag.String("toggle", "", "Toggle task status (provide task ID)")
-	deleteFlag := flag.String("delete", "", "Delete a task (provide task ID)")
-	clearFlag := flag.Bool("clear", false, "Clear all tasks")
-	completeAllFlag := flag.Bool("complete-all", false, "Mark all tasks as complete")
+// setCompletedAt отмечает задачу выполненной с указанным временем завершения
+// Используется для переноса истории выполнения из других программ, поэтому
+// уже выполненная задача получает новое время, а повторение не срабатывает.
+// Защищённая задача изменяется только при force
+func setCompletedAt(tl *TodoList, id int, force bool, t time.Time) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
 
-	flag.Parse()
+	if err := checkLocked(tl, id, force); err != nil {
+		return err
+	}
 
-	tl, err := loadTasks()
+	task := &tl.Tasks[index]
+	wasDone := task.Done
+	markDone(task, t)
+	if !wasDone {
+		recordEvent(task, eventComplete, t)
+	}
+	return nil
+}
+
+// completeTask отмечает задачу с указанным ID как выполненную
+// Защищённая задача выполняется только при force
+func completeTask(tl *TodoList, id int, force bool, now time.Time) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	if err := checkLocked(tl, id, force); err != nil {
+		return err
+	}
+
+	if tl.Tasks[index].Done {
+		return fmt.Errorf(msg("Задача #%d уже выполнена"), id)
+	}
+
+	finishTask(tl, index, eventComplete, now)
+	return nil
+}
+
+// completeWithNote отмечает задачу выполненной и дописывает к её заметкам заметку о выполнении
+// Заметка проверяется заранее, поэтому при любой ошибке список не меняется
+func completeWithNote(tl *TodoList, id int, note string, force bool, now time.Time) error {
+	note = strings.TrimSpace(note)
+	if err := validateNote(note); err != nil {
+		return err
+	}
+
+	if err := completeTask(tl, id, force, now); err != nil {
+		return err
+	}
+
+	return setNotes(tl, id, note, true)
+}
+
+// completeSilent отмечает задачу выполненной и возвращает её ID и строку для скриптов вида "3 done"
+func completeSilent(tl *TodoList, strId string, force bool, now time.Time) (int, string, error) {
+	id, err := strconv.Atoi(strId)
 	if err != nil {
-		fmt.Printf("Ошибка загрузки задач: %v\n", err)
-		return
+		return 0, "", errors.New(msg("Ошибка: не верный id"))
 	}
 
-	if *listFlag {
-		listTasks(tl)
-		return
+	if err := completeTask(tl, id, force, now); err != nil {
+		return 0, "", err
 	}
 
-	if *addFlag != "" {
-		content := *addFlag
-		addTask(tl, content)
-		if err := saveTask(tl); err != nil {
-			fmt.Printf("Ошибка сохранения задач: %v\n", err.Error())
-			return
-		}
-		return
+	return id, fmt.Sprintf("%d %s", id, statusDone), nil
+}
+
+// completeAndAdd отмечает задачу выполненной и сразу добавляет следующую
+// Новая задача проверяется заранее, поэтому при любой ошибке список не меняется
+func completeAndAdd(tl *TodoList, id int, content string, opts AddOptions, force bool, now time.Time) (Task, error) {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return Task{}, errors.New(msg("Задача не найдена"))
 	}
 
-	if *toggleFlag != "" {
-		id := *toggleFlag
-		toggleTask(tl, id)
-		if err := saveTask(tl); err != nil {
-			fmt.Printf("Ошибка сохранения задач: %v\n", err.Error())
-			return
-		}
-		return
+	if err := checkLocked(tl, id, force); err != nil {
+		return Task{}, err
 	}
 
-	if *deleteFlag != "" {
-		id := *deleteFlag
-		deleteTask(tl, id)
-		if err := saveTask(tl); err != nil {
-			fmt.Printf("Ошибка сохранения задач: %v\n", err)
-			return
-		}
-		return
+	if tl.Tasks[index].Done {
+		return Task{}, fmt.Errorf(msg("Задача #%d уже выполнена"), id)
 	}
 
-	if *clearFlag {
-		clearAllTasks(tl)
-		if err := saveTask(tl); err != nil {
-			fmt.Printf("Ошибка сохранения задач: %v\n", err)
-			return
-		}
-		return
+	if err := validateTask(tl, buildTask(tl, content, opts, now), opts.CaseSensitiveDupes); err != nil {
+		return Task{}, err
 	}
 
-	if *completeAllFlag {
-		completeAllTasks(tl)
-		if err := saveTask(tl); err != nil {
-			fmt.Printf("Ошибка сохранения задач: %v\n", err)
-			return
-		}
+	if err := completeTask(tl, id, force, now); err != nil {
+		return Task{}, err
+	}
+
+	return createTask(tl, content, opts, now)
+}
+
+// removeTask удаляет задачу с указанным ID из списка
+// Защищённая задача удаляется только при force
+func removeTask(tl *TodoList, id int, force bool) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	if err := checkLocked(tl, id, force); err != nil {
+		return err
+	}
+
+	tl.Tasks = append(tl.Tasks[:index], tl.Tasks[index+1:]...)
+	return nil
+}
+
+// deleteTask удаляет задачу из списка по её ID
+// При ошибке список не изменяется
+func deleteTask(tl *TodoList, strId string, force bool) error {
+	id, err := strconv.Atoi(strId)
+	if err != nil {
+		return errors.New(msg("Ошибка: не верный id"))
+	}
+
+	if err := removeTask(tl, id, force); err != nil {
+		return err
+	}
+
+	fmt.Printf(msg("Задача #%d была удалена\n"), id)
+	return nil
+}
+
+// clearAllTasks удаляет все задачи и сбрасывает счётчик ID
+// Если в списке есть защищённые задачи, без force список не изменяется
+func clearAllTasks(tl *TodoList, force bool) error {
+	var locked []int
+	for _, task := range tl.Tasks {
+		if protected(task, force) {
+			locked = append(locked, task.Id)
+		}
+	}
+	if len(locked) > 0 {
+		return fmt.Errorf(msg("Ошибка: защищены задачи %s, используйте --unlock или --force"), formatIds(locked))
+	}
+
+	tl.Tasks = []Task{}
+	tl.NextId = 1
+	fmt.Println(msg("Все задачи очищены"))
+	return nil
+}
+
+// markDone отмечает задачу как выполненную и проставляет время завершения
+func markDone(task *Task, now time.Time) {
+	task.Done = true
+	task.CompletedAt = now.Format(timeLayout)
+}
+
+// finishTask отмечает задачу с индексом index выполненной, записывает в историю событие kind
+// и создаёт следующее повторение повторяющейся задачи. Через неё проходят все команды,
+// выполняющие задачи. Повторение добавляется в конец списка, поэтому указатели на задачи
+// после вызова использовать нельзя
+func finishTask(tl *TodoList, index int, kind string, now time.Time) {
+	task := &tl.Tasks[index]
+	markDone(task, now)
+	recordEvent(task, kind, now)
+	regenerateRecurring(tl, task.Id, now)
+}
+
+// markPending снимает отметку о выполнении и очищает время завершения
+func markPending(task *Task) {
+	task.Done = false
+	task.CompletedAt = ""
+}
+
+// completeAllTasks отмечает выполненными все невыполненные задачи, подходящие под условие match
+// Заблокированные и защищённые задачи пропускаются, если не указан force; задача, зависящая только от задач,
+// выполненных этой же командой, тоже выполняется. Созданные при этом повторения остаются невыполненными.
+// Возвращает количество выполненных задач и ID пропущенных
+func completeAllTasks(tl *TodoList, match func(Task) bool, force bool, now time.Time) (int, []int) {
+	n := len(tl.Tasks)
+	completed := 0
+	for changed := true; changed; {
+		changed = false
+		for i := range n {
+			task := tl.Tasks[i]
+			if task.Done || !match(task) || protected(task, force) || !force && isBlocked(tl, task) {
+				continue
+			}
+
+			finishTask(tl, i, eventComplete, now)
+			completed++
+			changed = true
+		}
+	}
+
+	var skipped []int
+	for _, task := range tl.Tasks[:n] {
+		if !task.Done && match(task) {
+			skipped = append(skipped, task.Id)
+		}
+	}
+
+	return completed, skipped
+}
+
+// invertAll меняет статус выполнения каждой задачи на противоположный
+// Время завершения проставляется только задачам, ставшим выполненными; созданные при этом повторения не меняются
+// Защищённые задачи без force не меняются. Возвращает количество задач, ставших выполненными
+// и невыполненными, и ID пропущенных защищённых задач
+func invertAll(tl *TodoList, force bool, now time.Time) (int, int, []int) {
+	done, pending := 0, 0
+	var skipped []int
+	for i := range len(tl.Tasks) {
+		if protected(tl.Tasks[i], force) {
+			skipped = append(skipped, tl.Tasks[i].Id)
+			continue
+		}
+
+		if !tl.Tasks[i].Done {
+			finishTask(tl, i, eventToggle, now)
+			done++
+			continue
+		}
+
+		markPending(&tl.Tasks[i])
+		recordEvent(&tl.Tasks[i], eventToggle, now)
+		pending++
+	}
+
+	return done, pending, skipped
+}
+
+// lastTaskIndex возвращает индекс последней добавленной задачи
+// Последней считается задача с наибольшим ID, так как ID выдаются по возрастанию
+// Возвращает -1, если список пуст
+func lastTaskIndex(tl *TodoList) int {
+	index := -1
+	for i := range tl.Tasks {
+		if index == -1 || tl.Tasks[i].Id > tl.Tasks[index].Id {
+			index = i
+		}
+	}
+
+	return index
+}
+
+// oldestUncompleted находит невыполненную задачу, созданную раньше всех
+// Задачи с некорректной датой создания пропускаются
+func oldestUncompleted(tl *TodoList, now time.Time) (*Task, bool) {
+	var oldest *Task
+	var maxAge time.Duration
+	for i := range tl.Tasks {
+		if tl.Tasks[i].Done {
+			continue
+		}
+
+		createdAt, err := parseTime(tl.Tasks[i].CreatedAt)
+		if err != nil {
+			continue
+		}
+
+		if age := now.Sub(createdAt); oldest == nil || age > maxAge {
+			oldest = &tl.Tasks[i]
+			maxAge = age
+		}
+	}
+
+	return oldest, oldest != nil
+}
+
+// printOldestUncompleted выводит самую старую невыполненную задачу и её возраст
+func printOldestUncompleted(tl *TodoList, now time.Time) {
+	task, ok := oldestUncompleted(tl, now)
+	if !ok {
+		fmt.Println(msg("Всё сделано!"))
+		return
+	}
+
+	createdAt, _ := parseTime(task.CreatedAt)
+	fmt.Printf(msg("Самая старая задача #%d: %s (ждёт %s)\n"), task.Id, task.Content, humanizeAge(now.Sub(createdAt)))
+}
+
+// completeLastTask отмечает последнюю добавленную задачу как выполненную
+// Защищённая задача выполняется только при force
+func completeLastTask(tl *TodoList, force bool, now time.Time) {
+	index := lastTaskIndex(tl)
+	if index == -1 {
+		fmt.Println(msg("Список задач пуст"))
+		return
+	}
+
+	id := tl.Tasks[index].Id
+	if tl.Tasks[index].Done {
+		fmt.Printf(msg("Задача #%d уже выполнена\n"), id)
+		return
+	}
+
+	if err := checkLocked(tl, id, force); err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	finishTask(tl, index, eventComplete, now)
+	fmt.Printf(msg("Задача #%d отмечена как выполнено\n"), id)
+}
+
+// completeOldest отмечает выполненными n самых старых невыполненных задач
+// Если невыполненных задач меньше n, выполняются все. Защищённые задачи без force пропускаются.
+// Возвращает ID выполненных задач
+func completeOldest(tl *TodoList, n int, force bool, now time.Time) []int {
+	// Задачи выбираются по копии списка заранее, чтобы созданные повторения не попали в выборку;
+	// защищённые задачи в копии отмечаются выполненными, чтобы их пропустить
+	pending := &TodoList{Tasks: slices.Clone(tl.Tasks)}
+	for i := range pending.Tasks {
+		if protected(pending.Tasks[i], force) {
+			pending.Tasks[i].Done = true
+		}
+	}
+	var indexes []int
+	for len(indexes) < n {
+		task, ok := oldestUncompleted(pending, now)
+		if !ok {
+			break
+		}
+
+		task.Done = true
+		for i := range pending.Tasks {
+			if &pending.Tasks[i] == task {
+				indexes = append(indexes, i)
+			}
+		}
+	}
+
+	var ids []int
+	for _, index := range indexes {
+		ids = append(ids, tl.Tasks[index].Id)
+		finishTask(tl, index, eventComplete, now)
+	}
+
+	return ids
+}
+
+func main() {
+	listFlag := flag.Bool("list", false, "List all tasks")
+	treeFlag := flag.Bool("tree", false, "List tasks (honoring filters and --sort) with their subtasks indented below")
+	addFlag := flag.String("add", "", "Add a new task")
+	toggleFlag := flag.String("toggle", "", "Toggle task status (provide task ID)")
+	lockFlag := flag.String("lock", "", "Protect a task from deletion and status changes unless --force (provide task ID)")
+	unlockFlag := flag.String("unlock", "", "Remove the protection set by --lock (provide task ID)")
+	deleteFlag := flag.String("delete", "", "Delete a task (provide task ID)")
+	clearFlag := flag.Bool("clear", false, "Clear all tasks")
+	completeAllFlag := flag.Bool("complete-all", false, "Mark all tasks as complete, skipping blocked ones unless --force")
+	completeAllTagFlag := flag.String("complete-all-tag", "", "Mark all tasks with the given tag as complete, skipping blocked ones unless --force")
+	forceFlag := flag.Bool("force", false, "Save changes to a frozen list and delete or change the status of locked tasks; with --complete-all or --complete-all-tag, complete blocked tasks too")
+	freezeFlag := flag.Bool("freeze", false, "Freeze the task list so that changes are refused without --force")
+	unfreezeFlag := flag.Bool("unfreeze", false, "Unfreeze the task list")
+	tagsFlag := flag.String("tags", "", "Comma-separated tags for the new task")
+	asFlag := flag.String("as", "", "Record the new task as created by this user (defaults to $USER)")
+	statusFlag := flag.String("status", "", "Filter tasks by status: pending or done")
+	filterTagFlag := flag.String("filter-tag", "", "Filter tasks by tag")
+	filterPriorityFlag := flag.String("filter-priority", "", "List only tasks with the given priority: high, medium or low")
+	sinceIdFlag := flag.Int("since-id", 0, "Filter tasks whose ID is greater than N (combines with other filters)")
+	hasDueFlag := flag.Bool("has-due", false, "List only tasks with a due date")
+	noDueFlag := flag.Bool("no-due", false, "List only tasks without a due date")
+	searchFlag := flag.String("search", "", "List only tasks whose content contains the given text (case-insensitive)")
+	deduplicateTagsFlag := flag.Bool("deduplicate-tags", false, "Lowercase all tags and drop duplicate tags within each task")
+	tagAddBulkFlag := flag.String("tag-add-bulk", "", "Add a tag to every task matching the filters and --id-range")
+	idRangeFlag := flag.String("id-range", "", "With --tag-add-bulk, limit to tasks in an ID range (e.g. 3-7)")
+	assigneeFlag := flag.String("assignee", "", "Assignee for --add")
+	assignFlag := flag.String("assign", "", "Set or clear a task assignee (provide task ID and name)")
+	unusedTagsFlag := flag.String("unused-tags", "", "Print which of the given comma-separated tags are not used by any task")
+	topTagsFlag := flag.Int("top-tags", 0, "Print the N most used tags by task count")
+	topAssigneesFlag := flag.Int("top-assignees", 0, "Print the N assignees with the most tasks")
+	completedByAssigneeFlag := flag.Bool("completed-count-by-assignee", false, "Count completed tasks per assignee")
+	filterCreatorFlag := flag.String("filter-creator", "", "List only tasks created by the given user")
+	editInEditorFlag := flag.Bool("edit-in-editor", false, "Compose the new task in $EDITOR (first line is the content, the rest becomes notes; reads stdin if $EDITOR is unset)")
+	validateOnlyFlag := flag.Bool("validate-only", false, "With --add, only check whether the task would be accepted and print OK or the error")
+	notesFlag := flag.String("notes", "", "Notes for the new task")
+	setNotesFlag := flag.String("set-notes", "", "Replace task notes (provide task ID and text, empty text clears)")
+	appendNotesFlag := flag.String("append-notes", "", "Append to task notes (provide task ID and text)")
+	dueFlag := flag.String("due", "", "Due date for the new task (2006-01-02, today, tomorrow, +3d, +1w)")
+	bumpFlag := flag.String("bump", "", "Reset a task's creation time to now (provide task ID)")
+	setRecurFlag := flag.String("set-recur", "", "Make a task repeat when completed (provide task ID and daily, weekly, monthly or none)")
+	recurUntilFlag := flag.String("recur-until", "", "Stop repeating a task after a date (provide task ID and date, empty clears)")
+	setDueFlag := flag.String("set-due", "", "Set or clear a task due date (provide task ID and date)")
+	prependFlag := flag.Bool("prepend", false, "Insert the new task at the top of the list")
+	positionFlag := flag.Int("position", 0, "Insert the new task at the given position (starting from 1)")
+	caseSensitiveDupesFlag := flag.Bool("case-sensitive-dupes", false, "Treat tasks differing only in case as distinct")
+	oldestFlag := flag.Bool("oldest-uncompleted", false, "Show the oldest pending task")
+	sortFlag := flag.String("sort", "", "Sort the listing by id, created, content, due or status")
+	sortPersistFlag := flag.String("sort-persist", "", "Permanently reorder stored tasks by id, created, content, due or status")
+	colorFlag := flag.String("color", "", "Color label for --add/--edit: red, green, yellow, blue, magenta, cyan or none")
+	noColorFlag := flag.Bool("no-color", false, "Disable colored output")
+	timeFormatFlag := flag.String("time-format", "", "Go reference-time layout for displayed timestamps, e.g. \"02.01.2006 15:04\"")
+	truncateFlag := flag.Int("truncate", 0, "Truncate task content in listings to N characters")
+	groupByTagFlag := flag.Bool("group-by-tag", false, "List tasks grouped under tag headers")
+	limitPerTagFlag := flag.Int("limit-per-tag", 0, "Group tasks by tag and show at most N tasks per tag")
+	blockFlag := flag.String("block", "", "Make a task depend on another task (provide task ID and blocker ID)")
+	unblockFlag := flag.String("unblock", "", "Remove a task dependency (provide task ID and blocker ID)")
+	estimateFlag := flag.String("estimate", "", "Estimate in hours for --add")
+	setCompletedAtFlag := flag.String("set-completed-at", "", "Mark a task done with the given completion time (provide task ID and time as \"2006-01-02 15:04:05\")")
+	setEstimateFlag := flag.String("set-estimate", "", "Set or clear a task estimate in hours (provide task ID and hours)")
+	estimateRemainingFlag := flag.Bool("estimate-remaining", false, "Show remaining estimated hours and a forecast based on the last 7 days")
+	priorityFlag := flag.String("priority", "", "Priority for --add: high, medium or low")
+	setPriorityFlag := flag.String("set-priority", "", "Set or clear a task priority (provide task ID and level)")
+	listRecentFlag := flag.Int("list-recent", 0, "List the N most recently created tasks, newest first")
+	setPriorityByTagFlag := flag.String("set-priority-by-tag", "", "Set the priority of every task with a tag (provide tag and level)")
+	reorderByPriorityFlag := flag.Bool("reorder-by-priority", false, "Reorder the stored list from high to low priority, keeping order within each level")
+	prioritizeOverdueFlag := flag.Bool("prioritize-overdue", false, "Raise the priority of overdue pending tasks to high")
+	listReadyFlag := flag.Bool("list-ready", false, "List pending tasks not waiting on other tasks, by priority then age")
+	listBlockedFlag := flag.Bool("list-blocked", false, "List pending tasks waiting on incomplete tasks")
+	completeIfOverdueFlag := flag.String("complete-if-overdue", "", "Mark overdue pending tasks with the given tag as complete")
+	listOverdueFlag := flag.Bool("list-overdue", false, "List pending tasks past their due date")
+	compactFlag := flag.Bool("compact", false, "Print a one-line summary of pending and overdue tasks")
+	dedupeFlag := flag.Bool("dedupe", false, "Merge tasks with identical content")
+	renameRangeFlag := flag.String("rename-range", "", "Add --prefix/--suffix to tasks in an ID range (e.g. 3-7)")
+	renameTagFlag := flag.String("rename-tag", "", "Add --prefix/--suffix to tasks with the given tag")
+	prefixFlag := flag.String("prefix", "", "Prefix for --rename-range/--rename-tag")
+	suffixFlag := flag.String("suffix", "", "Suffix for --rename-range/--rename-tag")
+	jsonFlag := flag.Bool("json", false, "Print tasks as pretty-printed JSON")
+	jsonCompactFlag := flag.Bool("json-compact", false, "Print tasks as minified single-line JSON")
+	fieldsFlag := flag.String("fields", "", "With --json/--json-compact, print only the given comma-separated fields (e.g. id,content,done)")
+	ndjsonMetaFlag := flag.Bool("list-json-lines-with-meta", false, "Print tasks (honoring filters) as NDJSON, each wrapped with the schema version and source file")
+	ndjsonFlag := flag.Bool("ndjson", false, "Print tasks (honoring filters) as newline-delimited JSON, one task per line")
+	showFlag := flag.String("show", "", "Show task details (provide task ID)")
+	focusFlag := flag.String("focus", "", "Show only one task with its subtasks (provide task ID)")
+	addSubtaskFlag := flag.String("add-subtask", "", "Add a subtask (provide task ID and text)")
+	syncSubtasksFlag := flag.Bool("mark-done-if-subtasks-complete", false, "Mark pending tasks whose subtasks are all done as complete")
+	toggleSubtaskFlag := flag.String("toggle-subtask", "", "Toggle a subtask (provide task ID and subtask number)")
+	exportJSONFlag := flag.String("export-json", "", "Export tasks (honoring filters) to a JSON file")
+	toggleByContentFlag := flag.String("toggle-by-content", "", "Toggle the status of the single task with the given content")
+	findIdRangeFlag := flag.Bool("find-id-range", false, "Print IDs of tasks matching the filters as a comma-separated list")
+	findIdFlag := flag.String("find-id", "", "Print IDs of tasks with the given content, one per line")
+	randomFlag := flag.Bool("random", false, "Pick a random pending task")
+	diffFlag := flag.String("diff", "", "Compare the task list with another task file")
+	mergeFlag := flag.String("merge", "", "Merge tasks from another task file")
+	importJSONFlag := flag.String("import-json", "", "Import tasks from a JSON array of objects with a content field")
+	warnDuplicatesFlag := flag.Bool("warn-duplicates", false, "When merging or importing, import duplicate tasks with a warning instead of skipping them")
+	exportTxtFlag := flag.String("export-txt", "", "Export tasks (honoring filters) to a plain text file")
+	templateFlag := flag.String("template", "", "Go text/template applied to each task for --export-txt")
+	findEmptyFlag := flag.Bool("find-empty", false, "List tasks with empty or whitespace-only content")
+	removeEmptyFlag := flag.Bool("remove-empty", false, "Delete tasks with empty or whitespace-only content")
+	reindexFlag := flag.Bool("reindex", false, "Renumber tasks sequentially from 1 in list order")
+	compactIdsFlag := flag.Bool("compact-ids", false, "Renumber tasks sequentially on load before running the command (changes IDs)")
+	reassignIdFlag := flag.String("reassign-id", "", "Repair: change the ID of the task at a list position (provide position and new ID)")
+	checkIntegrityFlag := flag.Bool("check-integrity", false, "Check the task file for consistency problems")
+	nearDuplicatesFlag := flag.Bool("find-near-duplicates", false, "Report pairs of tasks with similar content")
+	maxDistanceFlag := flag.Int("max-distance", 2, "Maximum edit distance for --find-near-duplicates")
+	moveUpFlag := flag.String("move-up", "", "Move a task one position up (provide task ID)")
+	moveDownFlag := flag.String("move-down", "", "Move a task one position down (provide task ID)")
+	exportCSVFlag := flag.String("export-csv", "", "Export tasks (honoring filters) to a CSV file")
+	exportSQLiteFlag := flag.String("export-sqlite", "", "Export tasks (honoring filters) to a SQLite database file")
+	exportHTMLFlag := flag.String("export-html", "", "Export tasks (honoring filters) to an HTML page")
+	wcFlag := flag.Bool("wc", false, "Count tasks and words in task contents (honoring filters)")
+	completedByHourFlag := flag.Bool("completed-by-hour", false, "Show how many tasks were completed at each hour of the day")
+	streakFlag := flag.Bool("streak", false, "Show the number of consecutive days with completed tasks")
+	sinceLastRunFlag := flag.Bool("since-last-run", false, "List tasks created or completed since the previous --since-last-run")
+	completedBetweenFlag := flag.Bool("completed-between", false, "List tasks completed between --from and --to (inclusive)")
+	fromFlag := flag.String("from", "", "Start date for --completed-between")
+	toFlag := flag.String("to", "", "End date for --completed-between (defaults to today)")
+	replaceFlag := flag.String("replace", "", "Regex find/replace in task contents (honoring filters; provide pattern and replacement)")
+	retitleCaseFlag := flag.String("retitle-case", "", "Change the case of task contents (honoring filters): title, sentence or lower")
+	normalizeFlag := flag.Bool("normalize", false, "Trim and collapse whitespace in all task contents")
+	statsFlag := flag.Bool("stats", false, "Show task statistics")
+	summaryEmailFlag := flag.Bool("summary-email", false, "Print a daily digest of completed, overdue and due-today tasks for pasting into an email")
+	statsJSONFlag := flag.Bool("stats-json", false, "Print task statistics as a single line of JSON")
+	minCompleteFlag := flag.Float64("min-complete", 0, "With --stats, exit non-zero if completion percent is below this threshold")
+	widthFlag := flag.Int("width", defaultBarWidth, "Progress bar width for --stats and --completed-by-hour")
+	setTagsFlag := flag.String("set-tags", "", "Replace task tags (provide task ID and comma-separated tags)")
+	addTagFlag := flag.String("add-tag", "", "Add a tag to a task (provide task ID and tag)")
+	removeTagFlag := flag.String("remove-tag", "", "Remove a tag from a task (provide task ID and tag)")
+	retagFlag := flag.String("retag", "", "Replace one tag with another on a single task (provide task ID, old tag and new tag)")
+	deleteAllTagFlag := flag.String("delete-all-tag", "", "Delete every task with the given tag")
+	swapStatusFlag := flag.Bool("swap-status", false, "Invert the status of every task")
+	historyFlag := flag.String("history", "", "Show the change history of a task (provide task ID)")
+	editFlag := flag.String("edit", "", "Replace task text (provide task ID and new text)")
+	appendToFlag := flag.String("append-to", "", "Append text to the end of a task's content (provide task ID and text)")
+	completeSilentFlag := flag.String("complete-silent", "", "Mark a task as complete and print only \"<id> done\"; errors go to stderr")
+	completeFlag := flag.String("complete", "", "Mark a task as complete (provide task ID)")
+	thenAddFlag := flag.String("then-add", "", "With --complete, add a follow-up task in the same save")
+	completeWithNoteFlag := flag.String("complete-with-note", "", "Mark a task as complete and append a note to it (provide task ID and note)")
+	completeOldestFlag := flag.Int("complete-oldest", 0, "Mark the N oldest pending tasks as complete")
+	completeLastFlag := flag.Bool("complete-last", false, "Mark the most recently added task as complete")
+	onCompleteFlag := flag.String("on-complete", "", "Command to run after a task is marked done (receives ID and content)")
+	interactiveFlag := flag.Bool("interactive", false, "Start an interactive session")
+	flushEveryFlag := flag.Int("flush-every", 1, "In interactive mode, save after every N changes (0 saves only on exit)")
+	batchFlag := flag.String("batch", "", "Run commands from a file, one per line, and save once")
+	profileFlag := flag.String("profile", defaultProfile, "Use a named task list profile")
+	renameProfileFlag := flag.String("rename-profile", "", "Rename a profile (provide old and new names)")
+	backupFlag := flag.Bool("backup", false, "Save a timestamped backup copy of the task file")
+	rotateBackupsFlag := flag.String("rotate-backups", "", "Delete old backups, keeping only the N most recent")
+	maxTasksFlag := flag.Int("max-tasks", 0, "Refuse to save more than N tasks (0 means unlimited)")
+	debugConfigFlag := flag.Bool("debug-config", false, "Print resolved settings and where each came from to stderr")
+	langFlag := flag.String("lang", "", "Message language: ru or en (defaults to LANG, then ru)")
+
+	flag.Parse()
+
+	selected, err := resolveLang(*langFlag, os.Getenv)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	lang = selected
+
+	if err := validateProfileName(*profileFlag); err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	tasksPath = profilePath(".", *profileFlag)
+	maxTasks = *maxTasksFlag
+	allowFrozen = *forceFlag
+	compactIds = *compactIdsFlag
+
+	if *debugConfigFlag {
+		setFlags := make(map[string]bool)
+		flag.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+		printSettings(resolveSettings(SettingsInput{
+			SetFlags:   setFlags,
+			Profile:    *profileFlag,
+			Lang:       lang,
+			Sort:       *sortFlag,
+			TimeFormat: *timeFormatFlag,
+		}, os.Getenv), os.Stderr)
+		return
+	}
+
+	if *renameProfileFlag != "" {
+		if err := renameProfile(".", *renameProfileFlag, flag.Arg(0)); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+		fmt.Printf(msg("Профиль %s переименован в %s\n"), *renameProfileFlag, flag.Arg(0))
+		return
+	}
+
+	if *backupFlag {
+		path, err := createBackup(".", *profileFlag, time.Now())
+		if err != nil {
+			fmt.Printf(msg("Ошибка создания резервной копии: %v\n"), err)
+			return
+		}
+		fmt.Printf(msg("Резервная копия сохранена в %s\n"), path)
+		return
+	}
+
+	if *rotateBackupsFlag != "" {
+		keep, err := strconv.Atoi(*rotateBackupsFlag)
+		if err != nil {
+			fmt.Println(msg("Ошибка: не верное количество копий"))
+			return
+		}
+
+		removed, err := rotateBackups(".", *profileFlag, keep)
+		if err != nil {
+			fmt.Printf(msg("Ошибка удаления резервных копий: %v\n"), err)
+			return
+		}
+		fmt.Printf(msg("Удалено резервных копий: %d\n"), len(removed))
+		return
+	}
+
+	filters := FilterOptions{
+		Status:  *statusFlag,
+		Tag:     *filterTagFlag,
+		Creator: *filterCreatorFlag,
+		Search:  *searchFlag,
+		HasDue:  *hasDueFlag,
+		NoDue:   *noDueFlag,
+		SinceId: *sinceIdFlag,
+	}
+	if filters.SinceId < 0 {
+		fmt.Println(msg("Ошибка: не верный id"))
+		return
+	}
+	if filters.HasDue && filters.NoDue {
+		fmt.Println(msg("Ошибка: --has-due и --no-due нельзя указывать вместе"))
+		return
+	}
+	if err := validateStatus(filters.Status); err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	if filters.Priority, err = normalizePriority(*filterPriorityFlag); err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	display := DisplayOptions{
+		Truncate: *truncateFlag,
+		Color:    !*noColorFlag && isTerminal(os.Stdout),
+
+		TimeFormat: *timeFormatFlag,
+	}
+	if err := validateTimeLayout(display.TimeFormat); err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	tl, err := loadTasks()
+	if err != nil {
+		fmt.Printf(msg("Ошибка загрузки задач: %v\n"), err)
+		return
+	}
+	loadedFrozen = tl.Frozen
+
+	if *freezeFlag || *unfreezeFlag {
+		if *freezeFlag && *unfreezeFlag {
+			fmt.Println(msg("Ошибка: --freeze и --unfreeze нельзя указывать вместе"))
+			return
+		}
+
+		// Переключение заморозки сохраняется всегда, иначе замороженный список нельзя разморозить
+		allowFrozen = true
+		requireWritable()
+		tl.Frozen = *freezeFlag
+		if tl.Frozen {
+			fmt.Println(msg("Список задач заморожен"))
+		} else {
+			fmt.Println(msg("Список задач разморожен"))
+		}
+		saveOrExit(tl)
+		return
+	}
+
+	if *interactiveFlag {
+		requireWritable()
+		if err := runInteractive(tl, os.Stdin, os.Stdout, *flushEveryFlag, *forceFlag); err != nil {
+			fmt.Fprintf(os.Stderr, msg("Ошибка сохранения задач: %v\n"), err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *groupByTagFlag || *limitPerTagFlag > 0 {
+		groups := groupByTag(&TodoList{Tasks: applyFilters(tl, filters)})
+		renderGroups(limitPerTag(groups, *limitPerTagFlag), os.Stdout, display)
+		return
+	}
+
+	if *batchFlag != "" {
+		requireWritable()
+		f, err := os.Open(*batchFlag)
+		if err != nil {
+			fmt.Printf(msg("Ошибка чтения файла команд: %v\n"), err)
+			return
+		}
+		defer f.Close()
+
+		changed, failed, err := runBatch(tl, f, *forceFlag, os.Stdout)
+		if err != nil {
+			fmt.Printf(msg("Ошибка чтения файла команд: %v\n"), err)
+			return
+		}
+		if changed {
+			saveOrExit(tl)
+		}
+		if failed > 0 {
+			fmt.Printf(msg("Строк с ошибками: %d\n"), failed)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *listFlag || *treeFlag {
+		tasks := applyFilters(tl, filters)
+		if *sortFlag != "" {
+			if err := sortTasks(tasks, *sortFlag); err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+		}
+
+		if *treeFlag {
+			renderTree(tasks, os.Stdout, display)
+			return
+		}
+		listTasks(tasks, os.Stdout, display)
+		return
+	}
+
+	if *jsonFlag || *jsonCompactFlag {
+		tasks := applyFilters(tl, filters)
+		if *fieldsFlag != "" {
+			fields, err := parseFields(*fieldsFlag)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			if err := writeFieldsJSON(tasks, fields, os.Stdout, *jsonCompactFlag); err != nil {
+				fmt.Printf(msg("Ошибка вывода задач: %v\n"), err)
+			}
+			return
+		}
+		if err := writeTasksJSON(tasks, os.Stdout, *jsonCompactFlag); err != nil {
+			fmt.Printf(msg("Ошибка вывода задач: %v\n"), err)
+		}
+		return
+	}
+
+	if *ndjsonMetaFlag {
+		if err := writeNDJSONWithMeta(applyFilters(tl, filters), tasksPath, os.Stdout); err != nil {
+			fmt.Printf(msg("Ошибка вывода задач: %v\n"), err)
+		}
+		return
+	}
+
+	if *ndjsonFlag {
+		if err := writeNDJSON(applyFilters(tl, filters), os.Stdout); err != nil {
+			fmt.Printf(msg("Ошибка вывода задач: %v\n"), err)
+		}
+		return
+	}
+
+	if *showFlag != "" || *focusFlag != "" {
+		strId := *showFlag
+		if *focusFlag != "" {
+			strId = *focusFlag
+		}
+
+		id, ok := parseTaskId(strId)
+		if !ok {
+			return
+		}
+
+		if *focusFlag != "" {
+			if err := focusTask(tl, id, os.Stdout, display.TimeFormat); err != nil {
+				fmt.Println(err.Error())
+			}
+			return
+		}
+
+		index := findTaskIndex(tl, id)
+		if index == -1 {
+			fmt.Println(msg("Задача не найдена"))
+			return
+		}
+		showTask(tl.Tasks[index], os.Stdout, display.TimeFormat)
+		return
+	}
+
+	if *historyFlag != "" {
+		id, ok := parseTaskId(*historyFlag)
+		if !ok {
+			return
+		}
+
+		if err := printHistory(tl, id, os.Stdout); err != nil {
+			fmt.Println(err.Error())
+		}
+		return
+	}
+
+	if *exportJSONFlag != "" {
+		tasks := applyFilters(tl, filters)
+		err := exportToFile(*exportJSONFlag, func(w io.Writer) error {
+			return exportTasksJSON(tasks, w)
+		})
+		if err != nil {
+			fmt.Printf(msg("Ошибка экспорта задач: %v\n"), err)
+			return
+		}
+
+		fmt.Printf(msg("Экспортировано задач: %d\n"), len(tasks))
+		return
+	}
+
+	if *findIdRangeFlag {
+		ids := matchingIds(tl, filters)
+		if len(ids) == 0 {
+			os.Exit(1)
+		}
+
+		fmt.Println(joinIds(ids))
+		return
+	}
+
+	if *findIdFlag != "" {
+		if err := printFoundIds(tl, *findIdFlag, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *randomFlag {
+		task, ok := pickRandom(rand.New(rand.NewSource(time.Now().UnixNano())), tl)
+		if !ok {
+			fmt.Println(msg("Нет невыполненных задач"))
+			return
+		}
+
+		fmt.Printf(msg("Займитесь задачей #%d: %s\n"), task.Id, task.Content)
+		return
+	}
+
+	if *exportTxtFlag != "" {
+		view := &TodoList{Tasks: applyFilters(tl, filters)}
+		err := exportToFile(*exportTxtFlag, func(w io.Writer) error {
+			return exportText(view, *templateFlag, w)
+		})
+		if err != nil {
+			fmt.Printf(msg("Ошибка экспорта задач: %v\n"), err)
+			return
+		}
+
+		fmt.Printf(msg("Экспортировано задач: %d\n"), len(view.Tasks))
+		return
+	}
+
+	if *findEmptyFlag {
+		ids := emptyTasks(tl)
+		if len(ids) == 0 {
+			fmt.Println(msg("Пустых задач нет"))
+			return
+		}
+
+		fmt.Printf(msg("Пустые задачи: %s\n"), formatIds(ids))
+		return
+	}
+
+	if *removeEmptyFlag {
+		requireWritable()
+		removed, skipped := removeEmpty(tl, *forceFlag)
+		fmt.Printf(msg("Удалено пустых задач: %d\n"), removed)
+		printLockedSkipped(skipped)
+		saveOrExit(tl)
+		return
+	}
+
+	if *reindexFlag {
+		requireWritable()
+		changed := reindex(tl)
+		fmt.Printf(msg("Перенумеровано задач: %d\n"), changed)
+		saveOrExit(tl)
+		return
+	}
+
+	if *reassignIdFlag != "" {
+		requireWritable()
+		pos, ok := parseTaskId(*reassignIdFlag)
+		if !ok {
+			return
+		}
+		newId, ok := parseTaskId(flag.Arg(0))
+		if !ok {
+			return
+		}
+
+		if err := reassignId(tl, pos, newId); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		fmt.Printf(msg("Задаче на позиции %d назначен ID %d\n"), pos, newId)
+		saveOrExit(tl)
+		return
+	}
+
+	if *checkIntegrityFlag {
+		problems := checkIntegrity(tl)
+		if len(problems) == 0 {
+			fmt.Println("OK")
+			return
+		}
+
+		for _, p := range problems {
+			fmt.Printf(msg("Проблема: %s\n"), p)
+		}
+		os.Exit(1)
+	}
+
+	if *nearDuplicatesFlag {
+		pairs := nearDuplicates(tl, *maxDistanceFlag)
+		if len(pairs) == 0 {
+			fmt.Println(msg("Похожих задач не найдено"))
+			return
+		}
+
+		fmt.Println(msg("Похожие задачи:"))
+		for _, p := range pairs {
+			a, b := tl.Tasks[findTaskIndex(tl, p[0])], tl.Tasks[findTaskIndex(tl, p[1])]
+			fmt.Printf("#%d %s ~ #%d %s\n", a.Id, a.Content, b.Id, b.Content)
+		}
+		return
+	}
+
+	if *exportCSVFlag != "" {
+		view := &TodoList{Tasks: applyFilters(tl, filters)}
+		err := exportToFile(*exportCSVFlag, func(w io.Writer) error {
+			return exportCSV(view, w)
+		})
+		if err != nil {
+			fmt.Printf(msg("Ошибка экспорта задач: %v\n"), err)
+			return
+		}
+
+		fmt.Printf(msg("Экспортировано задач: %d\n"), len(view.Tasks))
+		return
+	}
+
+	if *exportSQLiteFlag != "" {
+		view := &TodoList{Tasks: applyFilters(tl, filters)}
+		if err := exportSQLite(view, *exportSQLiteFlag); err != nil {
+			fmt.Printf(msg("Ошибка экспорта задач: %v\n"), err)
+			return
+		}
+
+		fmt.Printf(msg("Экспортировано задач: %d\n"), len(view.Tasks))
+		return
+	}
+
+	if *exportHTMLFlag != "" {
+		view := &TodoList{Tasks: applyFilters(tl, filters)}
+		err := exportToFile(*exportHTMLFlag, func(w io.Writer) error {
+			return exportHTML(view, w)
+		})
+		if err != nil {
+			fmt.Printf(msg("Ошибка экспорта задач: %v\n"), err)
+			return
+		}
+
+		fmt.Printf(msg("Экспортировано задач: %d\n"), len(view.Tasks))
+		return
+	}
+
+	if *sinceLastRunFlag {
+		path := lastRunPath(tasksPath)
+		since, err := loadLastRun(path)
+		if err != nil {
+			fmt.Printf(msg("Ошибка: %v\n"), err)
+			return
+		}
+
+		now := time.Now()
+		if since.IsZero() {
+			fmt.Println(msg("Первый просмотр, показаны все задачи"))
+		} else {
+			fmt.Printf(msg("Изменения с %s:\n"), since.Format(timeLayout))
+		}
+		printChangesSince(tl, since, os.Stdout)
+
+		if err := saveLastRun(path, now); err != nil {
+			fmt.Fprintf(os.Stderr, msg("Ошибка: не удалось сохранить отметку просмотра: %v\n"), err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *completedBetweenFlag {
+		now := time.Now()
+		var from time.Time
+		if *fromFlag != "" {
+			from, err = parseDate(*fromFlag, now)
+			if err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+		}
+
+		to := now
+		if *toFlag != "" {
+			to, err = parseDate(*toFlag, now)
+			if err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+		}
+		to = time.Date(to.Year(), to.Month(), to.Day(), 23, 59, 59, 0, to.Location())
+
+		tasks := completedBetween(tl, from, to)
+		if len(tasks) == 0 {
+			fmt.Println(msg("Нет задач, выполненных за этот период"))
+			return
+		}
+
+		fmt.Println(msg("Выполненные задачи:"))
+		for _, task := range tasks {
+			fmt.Printf("%s #%d %s\n", task.CompletedAt, task.Id, task.Content)
+		}
+		return
+	}
+
+	if *oldestFlag {
+		printOldestUncompleted(tl, time.Now())
+		return
+	}
+
+	if *listRecentFlag != 0 {
+		if *listRecentFlag < 0 {
+			fmt.Println(msg("Ошибка: количество задач должно быть положительным"))
+			return
+		}
+
+		listTasks(recentTasks(tl, *listRecentFlag), os.Stdout, display)
+		return
+	}
+
+	if *setPriorityByTagFlag != "" {
+		requireWritable()
+		changed, err := setPriorityByTag(tl, *setPriorityByTagFlag, flag.Arg(0))
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		fmt.Printf(msg("Приоритет изменён у задач: %d\n"), changed)
+		saveOrExit(tl)
+		return
+	}
+
+	if *reorderByPriorityFlag {
+		requireWritable()
+		reorderByPriority(tl)
+		fmt.Println(msg("Задачи упорядочены по приоритету"))
+		saveOrExit(tl)
+		return
+	}
+
+	if *prioritizeOverdueFlag {
+		requireWritable()
+		changed := escalateOverdue(tl, time.Now())
+		fmt.Printf(msg("Приоритет повышен у задач: %d\n"), changed)
+		saveOrExit(tl)
+		return
+	}
+
+	if *listReadyFlag {
+		tasks := readyTasks(tl)
+		if len(tasks) == 0 {
+			fmt.Println(msg("Нет задач, готовых к выполнению"))
+			return
+		}
+
+		listTasks(tasks, os.Stdout, display)
+		return
+	}
+
+	if *estimateRemainingFlag {
+		printEstimateRemaining(tl, time.Now(), os.Stdout)
+		return
+	}
+
+	if *setCompletedAtFlag != "" {
+		requireWritable()
+		id, ok := parseTaskId(*setCompletedAtFlag)
+		if !ok {
+			return
+		}
+
+		completedAt, err := parseTime(flag.Arg(0))
+		if err != nil {
+			fmt.Printf(msg("Ошибка: неверное время %q, ожидается формат %s\n"), flag.Arg(0), timeLayout)
+			return
+		}
+
+		if err := setCompletedAt(tl, id, *forceFlag, completedAt); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		fmt.Printf(msg("Задача #%d отмечена выполненной %s\n"), id, completedAt.Format(timeLayout))
+		saveOrExit(tl)
+		return
+	}
+
+	if *setEstimateFlag != "" {
+		requireWritable()
+		id, ok := parseTaskId(*setEstimateFlag)
+		if !ok {
+			return
+		}
+
+		if err := setEstimate(tl, id, flag.Arg(0)); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		fmt.Printf(msg("Оценка задачи #%d обновлена\n"), id)
+		saveOrExit(tl)
+		return
+	}
+
+	if *setPriorityFlag != "" {
+		requireWritable()
+		id, ok := parseTaskId(*setPriorityFlag)
+		if !ok {
+			return
+		}
+
+		if err := setPriority(tl, id, flag.Arg(0)); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		fmt.Printf(msg("Приоритет задачи #%d обновлён\n"), id)
+		saveOrExit(tl)
+		return
+	}
+
+	if *listBlockedFlag {
+		listBlocked(tl)
+		return
+	}
+
+	if *blockFlag != "" || *unblockFlag != "" {
+		requireWritable()
+		strId := *blockFlag
+		if *unblockFlag != "" {
+			strId = *unblockFlag
+		}
+
+		id, ok := parseTaskId(strId)
+		if !ok {
+			return
+		}
+		blocker, ok := parseTaskId(flag.Arg(0))
+		if !ok {
+			return
+		}
+
+		if *unblockFlag != "" {
+			if err := removeBlocker(tl, id, blocker); err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+			fmt.Printf(msg("Задача #%d больше не зависит от #%d\n"), id, blocker)
+		} else {
+			if err := addBlocker(tl, id, blocker); err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+			fmt.Printf(msg("Задача #%d теперь зависит от #%d\n"), id, blocker)
+		}
+
+		saveOrExit(tl)
+		return
+	}
+
+	if *completeIfOverdueFlag != "" {
+		requireWritable()
+		closed, skipped := autoCloseOverdue(tl, *completeIfOverdueFlag, *forceFlag, time.Now())
+		fmt.Printf(msg("Выполнено задач: %d\n"), closed)
+		printLockedSkipped(skipped)
+		saveOrExit(tl)
+		return
+	}
+
+	if *listOverdueFlag {
+		listOverdue(tl, time.Now())
+		return
+	}
+
+	if *compactFlag {
+		fmt.Println(compactSummary(tl, time.Now()))
+		return
+	}
+
+	if *unusedTagsFlag != "" {
+		unused := unusedTags(tl, parseTags(*unusedTagsFlag))
+		if len(unused) == 0 {
+			fmt.Println(msg("Все теги используются"))
+			return
+		}
+
+		fmt.Println(msg("Неиспользуемые теги:"))
+		for _, tag := range unused {
+			fmt.Println(tag)
+		}
+		return
+	}
+
+	if *topTagsFlag != 0 || *topAssigneesFlag != 0 {
+		if *topTagsFlag < 0 || *topAssigneesFlag < 0 {
+			fmt.Println(msg("Ошибка: количество должно быть положительным"))
+			return
+		}
+
+		if *topTagsFlag > 0 {
+			printTop(topN(tagCounts(tl), *topTagsFlag), msg("Популярные теги:"), msg("Нет задач с тегами"), os.Stdout)
+		}
+		if *topAssigneesFlag > 0 {
+			printTop(topN(assigneeCounts(tl), *topAssigneesFlag), msg("Исполнители с наибольшим числом задач:"), msg("Нет задач с исполнителями"), os.Stdout)
+		}
+		return
+	}
+
+	if *completedByAssigneeFlag {
+		printCompletedByAssignee(tl, os.Stdout)
+		return
+	}
+
+	if *assignFlag != "" {
+		requireWritable()
+		id, ok := parseTaskId(*assignFlag)
+		if !ok {
+			return
+		}
+
+		if err := setAssignee(tl, id, flag.Arg(0)); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		fmt.Printf(msg("Исполнитель задачи #%d обновлён\n"), id)
+		saveOrExit(tl)
+		return
+	}
+
+	if *wcFlag {
+		printWordCount(applyFilters(tl, filters), os.Stdout)
+		return
+	}
+
+	if *completedByHourFlag {
+		printCompletionsByHour(tl, *widthFlag, os.Stdout)
+		return
+	}
+
+	if *streakFlag {
+		fmt.Printf(msg("Дней подряд с выполненными задачами: %d\n"), completionStreak(tl, time.Now()))
+		return
+	}
+
+	if *summaryEmailFlag {
+		writeDigest(tl, time.Now(), os.Stdout)
+		return
+	}
+
+	if *statsJSONFlag {
+		if err := statsJSON(tl, time.Now(), os.Stdout); err != nil {
+			fmt.Printf(msg("Ошибка вывода статистики: %v\n"), err)
+		}
+		return
+	}
+
+	if *statsFlag {
+		now := time.Now()
+		printStats(tl, *widthFlag, now)
+		if err := checkCompletion(computeStats(tl, now), *minCompleteFlag); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *addFlag != "" || *editInEditorFlag {
+		if !*validateOnlyFlag {
+			requireWritable()
+			requireCapacity(tl, 1)
+		}
+		content := *addFlag
+		opts := AddOptions{
+			Tags:     parseTags(*tagsFlag),
+			Position: *positionFlag,
+			Creator:  resolveCreator(*asFlag, os.Getenv),
+			Assignee: *assigneeFlag,
+			Notes:    *notesFlag,
+
+			CaseSensitiveDupes: *caseSensitiveDupesFlag,
+			ValidateOnly:       *validateOnlyFlag,
+		}
+		if *editInEditorFlag {
+			text, err := readFromEditor(os.Getenv("EDITOR"), content, os.Stdin)
+			if err != nil {
+				fmt.Printf(msg("Ошибка редактора: %v\n"), err)
+				return
+			}
+
+			var notes string
+			content, notes = splitComposed(text)
+			if notes != "" {
+				opts.Notes = notes
+			}
+		}
+		if *prependFlag {
+			opts.Position = 1
+		}
+		if opts.Color, err = normalizeColor(*colorFlag); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+		if opts.Priority, err = normalizePriority(*priorityFlag); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+		if opts.Estimate, err = parseEstimate(*estimateFlag); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+		if *dueFlag != "" {
+			due, err := parseDate(*dueFlag, time.Now())
+			if err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+			opts.DueDate = due.Format(dateLayout)
+		}
+
+		if opts.ValidateOnly {
+			if _, err := createTask(tl, content, opts, time.Now()); err != nil {
+				fmt.Fprintln(os.Stderr, strings.TrimSpace(err.Error()))
+				os.Exit(1)
+			}
+			fmt.Println("OK")
+			return
+		}
+
+		addTask(tl, content, opts)
+		saveOrExit(tl)
+		return
+	}
+
+	if *lockFlag != "" || *unlockFlag != "" {
+		requireWritable()
+		strId, locked := *lockFlag, true
+		if *unlockFlag != "" {
+			strId, locked = *unlockFlag, false
+		}
+
+		id, ok := parseTaskId(strId)
+		if !ok {
+			return
+		}
+		if err := setLocked(tl, id, locked); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		if locked {
+			fmt.Printf(msg("Задача #%d защищена\n"), id)
+		} else {
+			fmt.Printf(msg("Защита задачи #%d снята\n"), id)
+		}
+		saveOrExit(tl)
+		return
+	}
+
+	if *toggleFlag != "" {
+		requireWritable()
+		id, done, err := toggleTask(tl, *toggleFlag, *forceFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		saveOrExit(tl)
+		if done {
+			notifyCompleted(*onCompleteFlag, tl, id)
+		}
+		return
+	}
+
+	if *toggleByContentFlag != "" {
+		requireWritable()
+		id, done, err := toggleByContent(tl, *toggleByContentFlag, *forceFlag, time.Now())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		status := msg("не выполнено")
+		if done {
+			status = msg("выполнено")
+		}
+		fmt.Printf(msg("Задача #%d отмечена как %s\n"), id, status)
+		saveOrExit(tl)
+		if done {
+			notifyCompleted(*onCompleteFlag, tl, id)
+		}
+		return
+	}
+
+	if *deleteFlag != "" {
+		requireWritable()
+		if err := deleteTask(tl, *deleteFlag, *forceFlag); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		saveOrExit(tl)
+		return
+	}
+
+	if *clearFlag {
+		requireWritable()
+		if err := clearAllTasks(tl, *forceFlag); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		saveOrExit(tl)
+		return
+	}
+
+	if *syncSubtasksFlag {
+		requireWritable()
+		completed, skipped := syncParentCompletion(tl, *forceFlag, time.Now())
+		fmt.Printf(msg("Выполнено задач: %d\n"), completed)
+		printLockedSkipped(skipped)
+		saveOrExit(tl)
+		return
+	}
+
+	if *completeAllFlag || *completeAllTagFlag != "" {
+		requireWritable()
+		match := func(Task) bool { return true }
+		if *completeAllTagFlag != "" {
+			match = func(task Task) bool { return hasTag(task, *completeAllTagFlag) }
+		}
+
+		completed, skipped := completeAllTasks(tl, match, *forceFlag, time.Now())
+		if *completeAllFlag && len(skipped) == 0 {
+			fmt.Println(msg("Все задачи отмечены как выполненные"))
+		} else {
+			fmt.Printf(msg("Выполнено задач: %d\n"), completed)
+		}
+		for _, id := range skipped {
+			task := tl.Tasks[findTaskIndex(tl, id)]
+			if protected(task, *forceFlag) {
+				printLockedSkipped([]int{id})
+				continue
+			}
+			fmt.Printf(msg("Задача #%d пропущена: ждёт %s\n"), id, formatIds(openBlockers(tl, task)))
+		}
+		saveOrExit(tl)
+		return
+	}
+
+	if *bumpFlag != "" {
+		requireWritable()
+		id, ok := parseTaskId(*bumpFlag)
+		if !ok {
+			return
+		}
+
+		if err := bumpTask(tl, id, time.Now()); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		fmt.Printf(msg("Время создания задачи #%d обновлено\n"), id)
+		saveOrExit(tl)
+		return
+	}
+
+	if *setRecurFlag != "" {
+		requireWritable()
+		id, ok := parseTaskId(*setRecurFlag)
+		if !ok {
+			return
+		}
+
+		if err := setRecur(tl, id, flag.Arg(0)); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		fmt.Printf(msg("Повторение задачи #%d обновлено\n"), id)
+		saveOrExit(tl)
+		return
+	}
+
+	if *recurUntilFlag != "" {
+		requireWritable()
+		id, ok := parseTaskId(*recurUntilFlag)
+		if !ok {
+			return
+		}
+
+		if err := setRecurUntil(tl, id, flag.Arg(0), time.Now()); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		fmt.Printf(msg("Окончание повторения задачи #%d обновлено\n"), id)
+		saveOrExit(tl)
+		return
+	}
+
+	if *setDueFlag != "" {
+		requireWritable()
+		id, ok := parseTaskId(*setDueFlag)
+		if !ok {
+			return
+		}
+
+		if err := setDueDate(tl, id, flag.Arg(0), time.Now()); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		fmt.Printf(msg("Срок задачи #%d обновлён\n"), id)
+		saveOrExit(tl)
+		return
+	}
+
+	if *setNotesFlag != "" || *appendNotesFlag != "" {
+		requireWritable()
+		strId, appendMode := *setNotesFlag, false
+		if *appendNotesFlag != "" {
+			strId, appendMode = *appendNotesFlag, true
+		}
+
+		id, ok := parseTaskId(strId)
+		if !ok {
+			return
+		}
+
+		if err := setNotes(tl, id, flag.Arg(0), appendMode); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		fmt.Printf(msg("Заметки задачи #%d обновлены\n"), id)
+		saveOrExit(tl)
+		return
+	}
+
+	if *addSubtaskFlag != "" {
+		requireWritable()
+		id, ok := parseTaskId(*addSubtaskFlag)
+		if !ok {
+			return
+		}
+
+		if err := addSubtask(tl, id, flag.Arg(0)); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		fmt.Printf(msg("Добавлена подзадача к задаче #%d: %s\n"), id, flag.Arg(0))
+		saveOrExit(tl)
+		return
+	}
+
+	if *toggleSubtaskFlag != "" {
+		requireWritable()
+		id, ok := parseTaskId(*toggleSubtaskFlag)
+		if !ok {
+			return
+		}
+
+		n, err := strconv.Atoi(flag.Arg(0))
+		if err != nil {
+			fmt.Println(msg("Ошибка: не верный номер подзадачи"))
+			return
+		}
+
+		if err := toggleSubtask(tl, id, n); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		fmt.Printf(msg("Подзадача %d задачи #%d изменена\n"), n, id)
+		saveOrExit(tl)
+		return
+	}
+
+	if *setTagsFlag != "" || *addTagFlag != "" || *removeTagFlag != "" {
+		requireWritable()
+		strId := *setTagsFlag + *addTagFlag + *removeTagFlag
+		id, ok := parseTaskId(strId)
+		if !ok {
+			return
+		}
+
+		var tags []string
+		var err error
+		switch {
+		case *setTagsFlag != "":
+			tags, err = setTags(tl, id, strings.Split(flag.Arg(0), ","))
+		case *addTagFlag != "":
+			tags, err = addTag(tl, id, flag.Arg(0))
+		default:
+			tags, err = removeTag(tl, id, flag.Arg(0))
+		}
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		fmt.Printf(msg("Теги задачи #%d: %s\n"), id, formatTags(tags))
+		saveOrExit(tl)
+		return
+	}
+
+	if *retagFlag != "" {
+		requireWritable()
+		id, ok := parseTaskId(*retagFlag)
+		if !ok {
+			return
+		}
+
+		if err := retagTask(tl, id, flag.Arg(0), flag.Arg(1)); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		fmt.Printf(msg("Теги задачи #%d: %s\n"), id, formatTags(tl.Tasks[findTaskIndex(tl, id)].Tags))
+		saveOrExit(tl)
+		return
+	}
+
+	if *deleteAllTagFlag != "" {
+		requireWritable()
+		removed, skipped := deleteByTag(tl, *deleteAllTagFlag, *forceFlag)
+		fmt.Printf(msg("Удалено задач с тегом %s: %d\n"), *deleteAllTagFlag, removed)
+		printLockedSkipped(skipped)
+		saveOrExit(tl)
+		return
+	}
+
+	if *deduplicateTagsFlag {
+		requireWritable()
+		changed := canonicalizeTags(tl)
+		fmt.Printf(msg("Теги исправлены у задач: %d\n"), changed)
+		saveOrExit(tl)
+		return
+	}
+
+	if *tagAddBulkFlag != "" {
+		requireWritable()
+		if strings.TrimSpace(*tagAddBulkFlag) == "" {
+			fmt.Println(msg("Ошибка: тег не может быть пустым"))
+			return
+		}
+
+		match := func(task Task) bool { return matchFilters(task, filters) }
+		if *idRangeFlag != "" {
+			from, to, err := parseIdRange(*idRangeFlag)
+			if err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+			match = func(task Task) bool {
+				return task.Id >= from && task.Id <= to && matchFilters(task, filters)
+			}
+		}
+
+		changed := addTagWhere(tl, *tagAddBulkFlag, match)
+		fmt.Printf(msg("Тег %s добавлен задачам: %d\n"), strings.TrimSpace(*tagAddBulkFlag), changed)
+		saveOrExit(tl)
+		return
+	}
+
+	if *renameRangeFlag != "" || *renameTagFlag != "" {
+		requireWritable()
+		match := func(task Task) bool { return hasTag(task, *renameTagFlag) }
+		if *renameRangeFlag != "" {
+			from, to, err := parseIdRange(*renameRangeFlag)
+			if err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+			match = func(task Task) bool { return task.Id >= from && task.Id <= to }
+		}
+
+		changed, skipped := applyTransform(tl, match, affix(*prefixFlag, *suffixFlag), time.Now())
+		fmt.Printf(msg("Изменено задач: %d\n"), changed)
+		for _, id := range skipped {
+			fmt.Printf(msg("Задача #%d пропущена: текст стал бы некорректным\n"), id)
+		}
+		saveOrExit(tl)
+		return
+	}
+
+	if *diffFlag != "" {
+		other, err := loadTodoFile(*diffFlag)
+		if err != nil {
+			fmt.Printf(msg("Ошибка загрузки задач: %v\n"), err)
+			return
+		}
+
+		printDiff(tl, other, *diffFlag, os.Stdout)
+		return
+	}
+
+	if *mergeFlag != "" {
+		requireWritable()
+		other, err := loadTodoFile(*mergeFlag)
+		if err != nil {
+			fmt.Printf(msg("Ошибка загрузки задач: %v\n"), err)
+			return
+		}
+
+		imported := importTasks(tl, other.Tasks, time.Now(), *warnDuplicatesFlag, os.Stderr)
+		fmt.Printf(msg("Добавлено задач: %d из %d\n"), imported, len(other.Tasks))
+		saveOrExit(tl)
+		return
+	}
+
+	if *sortPersistFlag != "" {
+		requireWritable()
+		if err := sortTasks(tl.Tasks, *sortPersistFlag); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		fmt.Println(msg("Внимание: порядок задач в файле изменён"))
+		fmt.Printf(msg("Задачи отсортированы по ключу %s\n"), *sortPersistFlag)
+		saveOrExit(tl)
+		return
+	}
+
+	if *moveUpFlag != "" || *moveDownFlag != "" {
+		requireWritable()
+		strId, move := *moveUpFlag, moveUp
+		if *moveDownFlag != "" {
+			strId, move = *moveDownFlag, moveDown
+		}
+
+		id, ok := parseTaskId(strId)
+		if !ok {
+			return
+		}
+
+		if err := move(tl, id); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		fmt.Printf(msg("Задача #%d перемещена\n"), id)
+		saveOrExit(tl)
+		return
+	}
+
+	if *normalizeFlag {
+		requireWritable()
+		all := func(Task) bool { return true }
+		changed, skipped := applyTransform(tl, all, normalizeContent, time.Now())
+		fmt.Printf(msg("Нормализовано задач: %d\n"), changed)
+		for _, id := range skipped {
+			fmt.Printf(msg("Задача #%d пропущена: текст стал бы некорректным\n"), id)
+		}
+		saveOrExit(tl)
+		return
+	}
+
+	if *replaceFlag != "" {
+		requireWritable()
+		transform, err := regexReplace(*replaceFlag, flag.Arg(0))
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		match := func(task Task) bool { return matchFilters(task, filters) }
+		changed, skipped := applyTransform(tl, match, transform, time.Now())
+		fmt.Printf(msg("Изменено задач: %d\n"), changed)
+		for _, id := range skipped {
+			fmt.Printf(msg("Задача #%d пропущена: текст стал бы некорректным\n"), id)
+		}
+		saveOrExit(tl)
+		return
+	}
+
+	if *retitleCaseFlag != "" {
+		requireWritable()
+		transform, err := caseTransform(*retitleCaseFlag)
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		match := func(task Task) bool { return matchFilters(task, filters) }
+		changed, skipped := applyTransform(tl, match, transform, time.Now())
+		fmt.Printf(msg("Изменено задач: %d\n"), changed)
+		for _, id := range skipped {
+			fmt.Printf(msg("Задача #%d пропущена: текст стал бы некорректным\n"), id)
+		}
+		saveOrExit(tl)
+		return
+	}
+
+	if *importJSONFlag != "" {
+		requireWritable()
+		f, err := os.Open(*importJSONFlag)
+		if err != nil {
+			fmt.Printf(msg("Ошибка чтения файла: %v\n"), err)
+			return
+		}
+		defer f.Close()
+
+		imported, err := importJSON(tl, f, time.Now(), *warnDuplicatesFlag, os.Stderr)
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		fmt.Printf(msg("Импортировано задач: %d\n"), imported)
+		saveOrExit(tl)
+		return
+	}
+
+	if *dedupeFlag {
+		requireWritable()
+		merged, skipped := dedupe(tl, *forceFlag)
+		fmt.Printf(msg("Объединено дубликатов: %d\n"), merged)
+		printLockedSkipped(skipped)
+		saveOrExit(tl)
+		return
+	}
+
+	if *swapStatusFlag {
+		requireWritable()
+		done, pending, skipped := invertAll(tl, *forceFlag, time.Now())
+		fmt.Printf(msg("Статус всех задач изменён: выполнено %d, не выполнено %d\n"), done, pending)
+		printLockedSkipped(skipped)
+		saveOrExit(tl)
+		return
+	}
+
+	if *appendToFlag != "" {
+		requireWritable()
+		id, ok := parseTaskId(*appendToFlag)
+		if !ok {
+			return
+		}
+
+		if err := appendContent(tl, id, flag.Arg(0)); err != nil {
+			fmt.Println(strings.TrimSpace(err.Error()))
+			return
+		}
+
+		fmt.Printf(msg("Задача #%d изменена\n"), id)
+		saveOrExit(tl)
+		return
+	}
+
+	if *editFlag != "" {
+		requireWritable()
+		id, ok := parseTaskId(*editFlag)
+		if !ok {
+			return
+		}
+
+		if _, err := normalizeColor(*colorFlag); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		content := flag.Arg(0)
+		if content != "" || *colorFlag == "" {
+			if err := editTask(tl, id, content, time.Now()); err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+		}
+		if *colorFlag != "" {
+			if err := setColor(tl, id, *colorFlag); err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+		}
+
+		fmt.Printf(msg("Задача #%d изменена\n"), id)
+		saveOrExit(tl)
+		return
+	}
+
+	if *completeSilentFlag != "" {
+		requireWritable()
+		id, line, err := completeSilent(tl, *completeSilentFlag, *forceFlag, time.Now())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		saveOrExit(tl)
+		fmt.Println(line)
+		notifyCompleted(*onCompleteFlag, tl, id)
+		return
+	}
+
+	if *completeFlag != "" {
+		requireWritable()
+		id, ok := parseTaskId(*completeFlag)
+		if !ok {
+			return
+		}
+
+		now := time.Now()
+		if *thenAddFlag != "" {
+			requireCapacity(tl, 1)
+			opts := AddOptions{
+				Tags:    parseTags(*tagsFlag),
+				Creator: resolveCreator(*asFlag, os.Getenv),
+
+				CaseSensitiveDupes: *caseSensitiveDupesFlag,
+			}
+			task, err := completeAndAdd(tl, id, *thenAddFlag, opts, *forceFlag, now)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			fmt.Printf(msg("Задача #%d отмечена как выполнено\n"), id)
+			fmt.Printf(msg("Добавлена задача %d: %s\n"), task.Id, task.Content)
+		} else {
+			if err := completeTask(tl, id, *forceFlag, now); err != nil {
+				fmt.Fprintln(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			fmt.Printf(msg("Задача #%d отмечена как выполнено\n"), id)
+		}
+
+		saveOrExit(tl)
+		notifyCompleted(*onCompleteFlag, tl, id)
+		return
+	}
+
+	if *completeWithNoteFlag != "" {
+		requireWritable()
+		id, ok := parseTaskId(*completeWithNoteFlag)
+		if !ok {
+			return
+		}
+
+		if err := completeWithNote(tl, id, flag.Arg(0), *forceFlag, time.Now()); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		fmt.Printf(msg("Задача #%d отмечена как выполнено\n"), id)
+		saveOrExit(tl)
+		notifyCompleted(*onCompleteFlag, tl, id)
+		return
+	}
+
+	if *completeOldestFlag != 0 {
+		requireWritable()
+		if *completeOldestFlag < 0 {
+			fmt.Println(msg("Ошибка: количество задач должно быть положительным"))
+			return
+		}
+
+		ids := completeOldest(tl, *completeOldestFlag, *forceFlag, time.Now())
+		fmt.Printf(msg("Выполнено задач: %d\n"), len(ids))
+		saveOrExit(tl)
+		for _, id := range ids {
+			notifyCompleted(*onCompleteFlag, tl, id)
+		}
+		return
+	}
+
+	if *completeLastFlag {
+		requireWritable()
+		index := lastTaskIndex(tl)
+		wasPending := index != -1 && !tl.Tasks[index].Done
+		completeLastTask(tl, *forceFlag, time.Now())
+		saveOrExit(tl)
+		if wasPending && tl.Tasks[index].Done {
+			notifyCompleted(*onCompleteFlag, tl, tl.Tasks[index].Id)
+		}
+		return
+	}
+
+	if flag.NArg() > 0 {
+		runSubcommand(tl, flag.Args(), *forceFlag)
 		return
 	}
 
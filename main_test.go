@@ -0,0 +1,882 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// testNow — фиксированное время для тестов
+var testNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)
+
+// newTestList создаёт список из задач с указанными текстами и ID по порядку начиная с 1
+func newTestList(contents ...string) *TodoList {
+	tl := &TodoList{NextId: 1}
+	for i, content := range contents {
+		tl.Tasks = append(tl.Tasks, Task{
+			Id:        tl.NextId,
+			Content:   content,
+			CreatedAt: testNow.Add(time.Duration(i-len(contents)) * time.Hour).Format(timeLayout),
+		})
+		tl.NextId++
+	}
+	return tl
+}
+
+func TestCompleteLastTask(t *testing.T) {
+	tl := newTestList("first", "second", "third")
+	// Порядок в списке не важен: последней считается задача с наибольшим ID
+	tl.Tasks[0], tl.Tasks[2] = tl.Tasks[2], tl.Tasks[0]
+
+	completeLastTask(tl, false, testNow)
+
+	for _, task := range tl.Tasks {
+		want := task.Id == 3
+		if task.Done != want {
+			t.Errorf("task #%d done = %v, want %v", task.Id, task.Done, want)
+		}
+	}
+	if got := tl.Tasks[0].CompletedAt; got != testNow.Format(timeLayout) {
+		t.Errorf("CompletedAt = %q", got)
+	}
+}
+
+func TestCompleteLastTaskEmpty(t *testing.T) {
+	tl := &TodoList{NextId: 1}
+	completeLastTask(tl, false, testNow)
+	if len(tl.Tasks) != 0 {
+		t.Errorf("tasks = %v", tl.Tasks)
+	}
+}
+
+func TestCreateTaskPosition(t *testing.T) {
+	tests := []struct {
+		name     string
+		position int
+		want     []int
+	}{
+		{"append", 0, []int{1, 2, 3, 4}},
+		{"prepend", 1, []int{4, 1, 2, 3}},
+		{"middle", 2, []int{1, 4, 2, 3}},
+		{"past end clamped", 10, []int{1, 2, 3, 4}},
+		{"negative clamped", -3, []int{4, 1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("a", "b", "c")
+			task, err := createTask(tl, "new", AddOptions{Position: tt.position}, testNow)
+			if err != nil {
+				t.Fatalf("createTask: %v", err)
+			}
+
+			if task.Id != 4 || tl.NextId != 5 {
+				t.Errorf("task.Id = %d, NextId = %d, want 4 and 5", task.Id, tl.NextId)
+			}
+			if got := taskIds(tl.Tasks); !slices.Equal(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateTaskPositionValidates(t *testing.T) {
+	tl := newTestList("a")
+	if _, err := createTask(tl, "A", AddOptions{Position: 1}, testNow); err == nil {
+		t.Fatal("expected a duplicate error")
+	}
+	if len(tl.Tasks) != 1 || tl.NextId != 2 {
+		t.Errorf("list changed: %v, NextId = %d", tl.Tasks, tl.NextId)
+	}
+}
+
+func TestCreateTaskValidateOnly(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"valid", "new task", false},
+		{"duplicate", "A", true},
+		{"empty", "   ", true},
+		{"too long", strings.Repeat("x", maxTaskLength+1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("a")
+			task, err := createTask(tl, tt.content, AddOptions{ValidateOnly: true}, testNow)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("createTask error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && task.Content != tt.content {
+				t.Errorf("task content = %q, want %q", task.Content, tt.content)
+			}
+			if len(tl.Tasks) != 1 || tl.NextId != 2 {
+				t.Errorf("list changed: %v, NextId = %d", taskIds(tl.Tasks), tl.NextId)
+			}
+		})
+	}
+}
+
+func TestValidateTaskDuplicates(t *testing.T) {
+	tests := []struct {
+		content       string
+		caseSensitive bool
+		wantErr       bool
+	}{
+		{"Shop", false, true},
+		{"shop", false, true},
+		{"SHOP", false, true},
+		{"Shop", true, true},
+		{"shop", true, false},
+		{"SHOP", true, false},
+		{"market", false, false},
+	}
+
+	tl := newTestList("Shop")
+	for _, tt := range tests {
+		err := validateTask(tl, Task{Id: tl.NextId, Content: tt.content}, tt.caseSensitive)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateTask(%q, caseSensitive=%v) error = %v, want error %v", tt.content, tt.caseSensitive, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCreateTaskCaseSensitiveDupes(t *testing.T) {
+	tl := newTestList("Shop")
+	if _, err := createTask(tl, "shop", AddOptions{}, testNow); err == nil {
+		t.Error("default mode accepted a case-only duplicate")
+	}
+	if _, err := createTask(tl, "shop", AddOptions{CaseSensitiveDupes: true}, testNow); err != nil {
+		t.Errorf("case-sensitive mode rejected a distinct task: %v", err)
+	}
+}
+
+func TestOldestUncompleted(t *testing.T) {
+	tl := newTestList("done long ago", "oldest pending", "bad date", "newer pending")
+	tl.Tasks[0].CreatedAt = "2020-01-01 00:00:00"
+	tl.Tasks[0].Done = true
+	tl.Tasks[1].CreatedAt = "2024-05-01 09:00:00"
+	tl.Tasks[2].CreatedAt = "not a date"
+	tl.Tasks[3].CreatedAt = "2024-05-30 09:00:00"
+
+	task, ok := oldestUncompleted(tl, testNow)
+	if !ok || task.Id != 2 {
+		t.Fatalf("oldestUncompleted = %v, %v, want task #2", task, ok)
+	}
+}
+
+func TestOldestUncompletedAllDone(t *testing.T) {
+	tl := newTestList("a", "b")
+	for i := range tl.Tasks {
+		tl.Tasks[i].Done = true
+	}
+
+	if task, ok := oldestUncompleted(tl, testNow); ok {
+		t.Errorf("oldestUncompleted = %v, want none", task)
+	}
+}
+
+func TestSetDueDate(t *testing.T) {
+	tl := newTestList("a")
+
+	steps := []struct {
+		due  string
+		want string
+	}{
+		{"2024-06-10", "2024-06-10"},
+		{"+3d", "2024-06-04"},
+		{"tomorrow", "2024-06-02"},
+		{"none", ""},
+		{"2024-07-01", "2024-07-01"},
+		{"", ""},
+	}
+
+	for _, step := range steps {
+		if err := setDueDate(tl, 1, step.due, testNow); err != nil {
+			t.Fatalf("setDueDate(%q): %v", step.due, err)
+		}
+		if got := tl.Tasks[0].DueDate; got != step.want {
+			t.Errorf("after setDueDate(%q) DueDate = %q, want %q", step.due, got, step.want)
+		}
+	}
+}
+
+func TestSetDueDateErrors(t *testing.T) {
+	tl := newTestList("a")
+	tl.Tasks[0].DueDate = "2024-06-10"
+
+	if err := setDueDate(tl, 1, "someday", testNow); err == nil {
+		t.Error("expected an error for an invalid date")
+	}
+	if err := setDueDate(tl, 2, "today", testNow); err == nil {
+		t.Error("expected an error for a missing task")
+	}
+	if got := tl.Tasks[0].DueDate; got != "2024-06-10" {
+		t.Errorf("DueDate changed to %q", got)
+	}
+}
+
+func TestResolveCreator(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	tests := []struct {
+		name string
+		as   string
+		env  map[string]string
+		want string
+	}{
+		{"override", "anna", map[string]string{"USER": "root"}, "anna"},
+		{"override trimmed", "  anna ", nil, "anna"},
+		{"env fallback", "", map[string]string{"USER": "ivan"}, "ivan"},
+		{"blank override uses env", "  ", map[string]string{"USER": "ivan"}, "ivan"},
+		{"unset", "", nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := resolveCreator(tt.as, env(tt.env)); got != tt.want {
+			t.Errorf("%s: resolveCreator = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadTodoFileWithoutOptionalFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	data := `{"tasks":[{"id":1,"content":"old","done":false,"created_at":"2024-01-01 10:00:00"}],"next_id":2}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tl, err := loadTodoFile(path)
+	if err != nil {
+		t.Fatalf("loadTodoFile: %v", err)
+	}
+	if len(tl.Tasks) != 1 || tl.Tasks[0].CreatedBy != "" || tl.NextId != 2 {
+		t.Errorf("loaded %+v", tl)
+	}
+}
+
+func TestInvertAll(t *testing.T) {
+	tl := newTestList("pending", "done")
+	tl.Tasks[1].Done = true
+	tl.Tasks[1].CompletedAt = "2024-05-01 10:00:00"
+
+	done, pending, _ := invertAll(tl, false, testNow)
+
+	if done != 1 || pending != 1 {
+		t.Errorf("invertAll = %d, %d; want 1, 1", done, pending)
+	}
+	if task := tl.Tasks[0]; !task.Done || task.CompletedAt != testNow.Format(timeLayout) {
+		t.Errorf("task #1 = done %v, completed at %q", task.Done, task.CompletedAt)
+	}
+	if task := tl.Tasks[1]; task.Done || task.CompletedAt != "" {
+		t.Errorf("task #2 = done %v, completed at %q", task.Done, task.CompletedAt)
+	}
+}
+
+func TestSaveTaskReadOnlyFile(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root ignores file permission bits")
+	}
+
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	original := `{"tasks":[],"next_id":1}`
+	if err := os.WriteFile(path, []byte(original), 0444); err != nil {
+		t.Fatal(err)
+	}
+
+	oldPath := tasksPath
+	tasksPath = path
+	defer func() { tasksPath = oldPath }()
+
+	want := "не удалось записать " + path + ": permission denied"
+	if err := checkWritable(path); err == nil || err.Error() != want {
+		t.Errorf("checkWritable error = %v, want %q", err, want)
+	}
+	if err := saveTask(newTestList("a")); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("saveTask error = %v, want %q", err, want)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != original {
+		t.Errorf("read-only file changed: %q, %v", data, err)
+	}
+}
+
+func TestSaveTaskMaxTasks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	original := `{"tasks":[],"next_id":1}`
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldPath, oldMax := tasksPath, maxTasks
+	tasksPath, maxTasks = path, 2
+	defer func() { tasksPath, maxTasks = oldPath, oldMax }()
+
+	want := "в списке 3 задач, что больше ограничения 2"
+	if err := saveTask(newTestList("a", "b", "c")); err == nil || err.Error() != want {
+		t.Errorf("saveTask error = %v, want %q", err, want)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != original {
+		t.Errorf("file changed over the limit: %q, %v", data, err)
+	}
+
+	if err := saveTask(newTestList("a", "b")); err != nil {
+		t.Errorf("saveTask at the limit: %v", err)
+	}
+
+	maxTasks = 0
+	if err := saveTask(newTestList("a", "b", "c")); err != nil {
+		t.Errorf("saveTask without a limit: %v", err)
+	}
+}
+
+func TestCheckCapacity(t *testing.T) {
+	oldMax := maxTasks
+	maxTasks = 2
+	defer func() { maxTasks = oldMax }()
+
+	want := "в списке 3 задач, что больше ограничения 2"
+	if err := checkCapacity(newTestList("a", "b"), 1); err == nil || err.Error() != want {
+		t.Errorf("checkCapacity error = %v, want %q", err, want)
+	}
+	if err := checkCapacity(newTestList("a"), 1); err != nil {
+		t.Errorf("checkCapacity up to the limit: %v", err)
+	}
+}
+
+func TestCheckWritableMissingFile(t *testing.T) {
+	if err := checkWritable(filepath.Join(t.TempDir(), "new.json")); err != nil {
+		t.Errorf("checkWritable for a new file in a writable dir: %v", err)
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"Купить молоко", 6, "Купит…"},
+		{"Купить", 6, "Купить"},
+		{"Купить", 7, "Купить"},
+		{"Купить", 1, "…"},
+		{"Купить", 0, "Купить"},
+		{"Купить", -1, "Купить"},
+		{"", 3, ""},
+	}
+
+	for _, tt := range tests {
+		got := truncateRunes(tt.s, tt.n)
+		if got != tt.want {
+			t.Errorf("truncateRunes(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("truncateRunes(%q, %d) split a multibyte character: %q", tt.s, tt.n, got)
+		}
+	}
+}
+
+func TestListTasksTruncateKeepsContent(t *testing.T) {
+	tl := newTestList("Купить молоко")
+
+	var buf bytes.Buffer
+	listTasks(tl.Tasks, &buf, DisplayOptions{Truncate: 6})
+
+	if !strings.Contains(buf.String(), "Купит…") || strings.Contains(buf.String(), "молоко") {
+		t.Errorf("listTasks output = %q", buf.String())
+	}
+	if tl.Tasks[0].Content != "Купить молоко" {
+		t.Errorf("stored content changed to %q", tl.Tasks[0].Content)
+	}
+}
+
+func TestPrintFoundIds(t *testing.T) {
+	tl := newTestList("buy milk", "call mom", "Buy Milk")
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{"unique", "call mom", "2\n", false},
+		{"multiple", "BUY MILK", "1\n3\n", false},
+		{"no match", "buy bread", "", true},
+		{"substring is not a match", "milk", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := printFoundIds(tl, tt.content, &buf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("printFoundIds error = %v, want error %v", err, tt.wantErr)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("output = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCompleteAndAddPersistsTogether(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	oldPath := tasksPath
+	tasksPath = path
+	defer func() { tasksPath = oldPath }()
+
+	tl := newTestList("write draft")
+	task, err := completeAndAdd(tl, 1, "send draft", AddOptions{}, false, testNow)
+	if err != nil {
+		t.Fatalf("completeAndAdd: %v", err)
+	}
+	if err := saveTask(tl); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := loadTodoFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Tasks) != 2 || !saved.Tasks[0].Done || saved.Tasks[1].Content != "send draft" || saved.Tasks[1].Id != task.Id {
+		t.Errorf("saved tasks = %+v", saved.Tasks)
+	}
+}
+
+func TestCompleteAndAddInvalidLeavesListUnchanged(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int
+		content string
+	}{
+		{"duplicate follow-up", 1, "WRITE DRAFT"},
+		{"duplicate follow-up with due token", 1, "write draft @tomorrow"},
+		{"empty follow-up", 1, ""},
+		{"missing task", 5, "next"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("write draft")
+			if _, err := completeAndAdd(tl, tt.id, tt.content, AddOptions{}, false, testNow); err == nil {
+				t.Fatal("expected an error")
+			}
+			if len(tl.Tasks) != 1 || tl.Tasks[0].Done || tl.NextId != 2 {
+				t.Errorf("list changed: %+v, NextId = %d", tl.Tasks, tl.NextId)
+			}
+		})
+	}
+}
+
+func TestBumpTask(t *testing.T) {
+	tl := newTestList("a", "b")
+	tl.Tasks[1].Tags = []string{"work"}
+	before := tl.Tasks[1]
+	later := testNow.Add(48 * time.Hour)
+
+	if err := bumpTask(tl, 2, later); err != nil {
+		t.Fatal(err)
+	}
+
+	task := tl.Tasks[1]
+	if task.CreatedAt != later.Format(timeLayout) {
+		t.Errorf("CreatedAt = %q, want %q", task.CreatedAt, later.Format(timeLayout))
+	}
+	if task.Id != before.Id || task.Content != before.Content || !slices.Equal(task.Tags, before.Tags) || tl.NextId != 3 {
+		t.Errorf("bumped task = %+v, NextId = %d", task, tl.NextId)
+	}
+	if tl.Tasks[0].CreatedAt == later.Format(timeLayout) {
+		t.Error("other task bumped")
+	}
+}
+
+func TestBumpTaskMissing(t *testing.T) {
+	if err := bumpTask(newTestList("a"), 2, testNow); err == nil {
+		t.Error("expected an error for a missing task")
+	}
+}
+
+func TestCompleteOldest(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		want []int
+	}{
+		{"two oldest", 2, []int{3, 1}},
+		{"more than pending", 10, []int{3, 1, 4}},
+		{"zero", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("b", "done", "a", "c")
+			tl.Tasks[0].CreatedAt = "2024-05-02 10:00:00"
+			tl.Tasks[1].CreatedAt = "2024-04-01 10:00:00"
+			markDone(&tl.Tasks[1], testNow.Add(-time.Hour))
+			tl.Tasks[2].CreatedAt = "2024-05-01 10:00:00"
+			tl.Tasks[3].CreatedAt = "2024-05-03 10:00:00"
+
+			got := completeOldest(tl, tt.n, false, testNow)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("completeOldest = %v, want %v", got, tt.want)
+			}
+			for _, id := range got {
+				task := tl.Tasks[findTaskIndex(tl, id)]
+				if !task.Done || task.CompletedAt != testNow.Format(timeLayout) {
+					t.Errorf("task #%d = done %v, completed at %q", id, task.Done, task.CompletedAt)
+				}
+			}
+			if got := tl.Tasks[1].CompletedAt; got != testNow.Add(-time.Hour).Format(timeLayout) {
+				t.Errorf("already done task restamped: %q", got)
+			}
+		})
+	}
+}
+
+func TestCompleteSilent(t *testing.T) {
+	tl := newTestList("a", "b", "c")
+
+	id, line, err := completeSilent(tl, "3", false, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 3 || line != "3 done" {
+		t.Errorf("completeSilent = %d, %q; want 3, \"3 done\"", id, line)
+	}
+	if !tl.Tasks[2].Done {
+		t.Error("task #3 not completed")
+	}
+
+	for _, strId := range []string{"", "x", "9"} {
+		if _, line, err := completeSilent(tl, strId, false, testNow); err == nil || line != "" {
+			t.Errorf("completeSilent(%q) = %q, %v; want an error", strId, line, err)
+		}
+	}
+}
+
+func TestCompleteAllTasksBlocked(t *testing.T) {
+	newList := func() *TodoList {
+		tl := newTestList("design", "build", "ship", "other")
+		for i := range 3 {
+			tl.Tasks[i].Tags = []string{"release"}
+		}
+		tl.Tasks[1].BlockedBy = []int{1} // Разблокируется этой же командой
+		tl.Tasks[2].BlockedBy = []int{4} // Зависит от задачи вне тега
+		return tl
+	}
+	byTag := func(task Task) bool { return hasTag(task, "release") }
+
+	tests := []struct {
+		name        string
+		force       bool
+		wantCount   int
+		wantSkipped []int
+		wantDone    []int
+	}{
+		{"blocked task skipped", false, 2, []int{3}, []int{1, 2}},
+		{"force overrides", true, 3, nil, []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newList()
+			completed, skipped := completeAllTasks(tl, byTag, tt.force, testNow)
+
+			if completed != tt.wantCount || !slices.Equal(skipped, tt.wantSkipped) {
+				t.Errorf("completeAllTasks = %d, %v; want %d, %v", completed, skipped, tt.wantCount, tt.wantSkipped)
+			}
+			var done []int
+			for _, task := range tl.Tasks {
+				if task.Done {
+					done = append(done, task.Id)
+				}
+			}
+			if !slices.Equal(done, tt.wantDone) {
+				t.Errorf("done = %v, want %v", done, tt.wantDone)
+			}
+		})
+	}
+}
+
+func TestToggleByContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantId   int
+		wantDone bool
+		wantErr  string
+	}{
+		{"unique", "  BUY milk ", 1, true, ""},
+		{"missing", "call mom", 0, false, "Задача не найдена"},
+		{"ambiguous", "read book", 0, false, "Ошибка: текст совпадает с несколькими задачами: #2, #3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("buy milk", "read book", "Read Book")
+
+			id, done, err := toggleByContent(tl, tt.content, false, testNow)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				for _, task := range tl.Tasks {
+					if task.Done {
+						t.Errorf("task #%d toggled despite the error", task.Id)
+					}
+				}
+				return
+			}
+
+			if err != nil || id != tt.wantId || done != tt.wantDone {
+				t.Errorf("toggleByContent = %d, %v, %v; want %d, %v", id, done, err, tt.wantId, tt.wantDone)
+			}
+			if tl.Tasks[0].Done != tt.wantDone {
+				t.Errorf("task #1 done = %v, want %v", tl.Tasks[0].Done, tt.wantDone)
+			}
+		})
+	}
+}
+
+func TestLoadTasksCompactIds(t *testing.T) {
+	tests := []struct {
+		name    string
+		compact bool
+		wantIds []int
+		wantBy  []int
+	}{
+		{"enabled closes gaps", true, []int{1, 2, 3}, []int{1}},
+		{"disabled keeps ids", false, []int{2, 5, 9}, []int{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tasks.json")
+			data := `{"tasks":[{"id":2,"content":"a"},{"id":5,"content":"b"},{"id":9,"content":"c","blocked_by":[2]}],"next_id":10}`
+			if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			oldPath, oldCompact := tasksPath, compactIds
+			tasksPath, compactIds = path, tt.compact
+			defer func() { tasksPath, compactIds = oldPath, oldCompact }()
+
+			tl, err := loadTasks()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := saveTask(tl); err != nil {
+				t.Fatal(err)
+			}
+
+			compactIds = false
+			saved, err := loadTasks()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := taskIds(saved.Tasks); !slices.Equal(got, tt.wantIds) {
+				t.Errorf("saved ids = %v, want %v", got, tt.wantIds)
+			}
+			if got := saved.Tasks[2].BlockedBy; !slices.Equal(got, tt.wantBy) {
+				t.Errorf("blocked by = %v, want %v", got, tt.wantBy)
+			}
+		})
+	}
+}
+
+func TestSetCompletedAt(t *testing.T) {
+	tests := []struct {
+		name        string
+		done        bool
+		wantHistory int
+	}{
+		{"pending task", false, 1},
+		{"already done gets a new time", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("a")
+			if tt.done {
+				markDone(&tl.Tasks[0], testNow)
+			}
+
+			at, err := parseTime("2023-12-31 23:59:00")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := setCompletedAt(tl, 1, false, at); err != nil {
+				t.Fatal(err)
+			}
+
+			task := tl.Tasks[0]
+			if !task.Done || task.CompletedAt != "2023-12-31 23:59:00" {
+				t.Errorf("task = done %v, completed at %q", task.Done, task.CompletedAt)
+			}
+			if len(task.History) != tt.wantHistory {
+				t.Errorf("history = %v, want %d events", task.History, tt.wantHistory)
+			}
+		})
+	}
+}
+
+func TestSetCompletedAtErrors(t *testing.T) {
+	for _, s := range []string{"2023-12-31", "31.12.2023 23:59:00", "2023-13-01 00:00:00", ""} {
+		if _, err := parseTime(s); err == nil {
+			t.Errorf("parseTime(%q): expected an error", s)
+		}
+	}
+
+	if err := setCompletedAt(newTestList("a"), 2, false, testNow); err == nil {
+		t.Error("setCompletedAt for a missing task: expected an error")
+	}
+}
+
+func TestFrozenList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	original := `{"tasks":[{"id":1,"content":"reference"}],"next_id":2,"frozen":true}`
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldPath, oldAllow := tasksPath, allowFrozen
+	tasksPath, allowFrozen = path, false
+	defer func() { tasksPath, allowFrozen = oldPath, oldAllow }()
+
+	mutations := []struct {
+		name string
+		op   func(tl *TodoList) error
+	}{
+		{"add", func(tl *TodoList) error { _, err := createTask(tl, "new", AddOptions{}, testNow); return err }},
+		{"delete", func(tl *TodoList) error { return removeTask(tl, 1, false) }},
+	}
+
+	for _, m := range mutations {
+		t.Run(m.name, func(t *testing.T) {
+			tl, err := loadTasks()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := m.op(tl); err != nil {
+				t.Fatal(err)
+			}
+
+			want := "список задач заморожен, используйте --unfreeze или --force"
+			if err := saveTask(tl); err == nil || err.Error() != want {
+				t.Errorf("saveTask error = %v, want %q", err, want)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil || string(data) != original {
+				t.Errorf("frozen file changed: %q, %v", data, err)
+			}
+		})
+	}
+
+	tl, err := loadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	listTasks(tl.Tasks, &buf, DisplayOptions{})
+	if !strings.Contains(buf.String(), "1 [ ], reference") {
+		t.Errorf("frozen list output = %q", buf.String())
+	}
+
+	allowFrozen = true
+	if err := saveTask(tl); err != nil {
+		t.Errorf("saveTask with force: %v", err)
+	}
+}
+
+func TestCompleteWithNotePersists(t *testing.T) {
+	oldPath := tasksPath
+	tasksPath = filepath.Join(t.TempDir(), "tasks.json")
+	defer func() { tasksPath = oldPath }()
+
+	tl := newTestList("a", "b")
+	tl.Tasks[0].Notes = "first"
+	if err := completeWithNote(tl, 1, "  done via phone ", false, testNow); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveTask(tl); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := loadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := saved.Tasks[0]
+	if !task.Done || task.CompletedAt != testNow.Format(timeLayout) {
+		t.Errorf("task = done %v, completed at %q", task.Done, task.CompletedAt)
+	}
+	if task.Notes != "first\ndone via phone" {
+		t.Errorf("notes = %q", task.Notes)
+	}
+}
+
+func TestCompleteWithNoteErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		id   int
+		note string
+	}{
+		{"empty note", 1, "  "},
+		{"note too long", 1, strings.Repeat("я", maxNoteLength+1)},
+		{"missing task", 3, "note"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("a", "b")
+			if err := completeWithNote(tl, tt.id, tt.note, false, testNow); err == nil {
+				t.Fatal("expected an error")
+			}
+			if tl.Tasks[0].Done || tl.Tasks[0].Notes != "" {
+				t.Errorf("task changed: %+v", tl.Tasks[0])
+			}
+		})
+	}
+}
+
+func TestAppendContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{"success", "  и хлеб ", "купить молоко" + appendSeparator + "и хлеб", false},
+		{"length overflow", strings.Repeat("x", maxTaskLength), "купить молоко", true},
+		{"duplicate result", "сыр", "купить молоко", true},
+		{"empty text", " ", "купить молоко", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("купить молоко", "купить молоко"+appendSeparator+"сыр")
+			err := appendContent(tl, 1, tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("appendContent error = %v, want error %v", err, tt.wantErr)
+			}
+			if tl.Tasks[0].Content != tt.want {
+				t.Errorf("content = %q, want %q", tl.Tasks[0].Content, tt.want)
+			}
+		})
+	}
+
+	if err := appendContent(newTestList("a"), 2, "b"); err == nil {
+		t.Error("appendContent for a missing task: expected an error")
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxNoteLength = 500 // Максимальная длина заметки о выполнении в символах
+
+// validateNote проверяет заметку о выполнении: она не пустая и не длиннее maxNoteLength
+func validateNote(note string) error {
+	if note == "" {
+		return errors.New(msg("Ошибка: заметка не может быть пустой"))
+	}
+	if utf8.RuneCountInString(note) > maxNoteLength {
+		return fmt.Errorf(msg("Ошибка: заметка не должна превышать %d символов"), maxNoteLength)
+	}
+	return nil
+}
+
+// setNotes заменяет заметки задачи или, при appendMode, дописывает их с новой строки
+// Пустое значение без appendMode очищает заметки
+func setNotes(tl *TodoList, id int, notes string, appendMode bool) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	task := &tl.Tasks[index]
+	notes = strings.TrimSpace(notes)
+	if appendMode && task.Notes != "" {
+		if notes == "" {
+			return nil
+		}
+		task.Notes += "\n" + notes
+		return nil
+	}
+
+	task.Notes = notes
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestSetNotes(t *testing.T) {
+	tests := []struct {
+		name       string
+		initial    string
+		notes      string
+		appendMode bool
+		want       string
+	}{
+		{"set", "", "first", false, "first"},
+		{"replace", "old", "new", false, "new"},
+		{"append", "old", "more", true, "old\nmore"},
+		{"append to empty", "", "first", true, "first"},
+		{"append empty keeps notes", "old", "  ", true, "old"},
+		{"clear", "old", "", false, ""},
+		{"trimmed", "", "  note  ", false, "note"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("a")
+			tl.Tasks[0].Notes = tt.initial
+
+			if err := setNotes(tl, 1, tt.notes, tt.appendMode); err != nil {
+				t.Fatalf("setNotes: %v", err)
+			}
+			if got := tl.Tasks[0].Notes; got != tt.want {
+				t.Errorf("Notes = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetNotesMissingTask(t *testing.T) {
+	if err := setNotes(newTestList("a"), 2, "note", false); err == nil {
+		t.Error("expected an error for a missing task")
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// swapTasks меняет местами задачи с индексами i и j
+func swapTasks(tl *TodoList, i, j int) {
+	tl.Tasks[i], tl.Tasks[j] = tl.Tasks[j], tl.Tasks[i]
+}
+
+// moveBy сдвигает задачу с указанным ID на одну позицию вверх (delta = -1) или вниз (delta = 1)
+// У границ списка задача остаётся на месте
+func moveBy(tl *TodoList, id, delta int) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	target := index + delta
+	if target < 0 || target >= len(tl.Tasks) {
+		return nil
+	}
+
+	swapTasks(tl, index, target)
+	return nil
+}
+
+// moveUp сдвигает задачу на одну позицию вверх
+func moveUp(tl *TodoList, id int) error {
+	return moveBy(tl, id, -1)
+}
+
+// moveDown сдвигает задачу на одну позицию вниз
+func moveDown(tl *TodoList, id int) error {
+	return moveBy(tl, id, 1)
+}
+
+// sortLess возвращает функцию сравнения задач для указанного ключа сортировки
+// Поддерживаемые ключи: id, created, content, due, status
+func sortLess(key string) (func(a, b Task) bool, error) {
+	switch key {
+	case "id":
+		return func(a, b Task) bool { return a.Id < b.Id }, nil
+	case "created":
+		return func(a, b Task) bool { return a.CreatedAt < b.CreatedAt }, nil
+	case "content":
+		return func(a, b Task) bool { return strings.ToLower(a.Content) < strings.ToLower(b.Content) }, nil
+	case "due":
+		// Задачи без срока идут в конце
+		return func(a, b Task) bool {
+			if a.DueDate == "" || b.DueDate == "" {
+				return a.DueDate != "" && b.DueDate == ""
+			}
+			return a.DueDate < b.DueDate
+		}, nil
+	case "status":
+		return func(a, b Task) bool { return !a.Done && b.Done }, nil
+	default:
+		return nil, fmt.Errorf(msg("Ошибка: не верный ключ сортировки %q (ожидается id, created, content, due или status)"), key)
+	}
+}
+
+// sortTasks сортирует задачи по ключу, сохраняя исходный порядок равных задач
+func sortTasks(tasks []Task, key string) error {
+	less, err := sortLess(key)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return less(tasks[i], tasks[j])
+	})
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMoveUpDown(t *testing.T) {
+	tests := []struct {
+		name string
+		move func(tl *TodoList, id int) error
+		id   int
+		want []int
+	}{
+		{"top up is a no-op", moveUp, 1, []int{1, 2, 3}},
+		{"middle down", moveDown, 2, []int{1, 3, 2}},
+		{"middle up", moveUp, 2, []int{2, 1, 3}},
+		{"bottom down is a no-op", moveDown, 3, []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("a", "b", "c")
+			if err := tt.move(tl, tt.id); err != nil {
+				t.Fatal(err)
+			}
+			if got := taskIds(tl.Tasks); !slices.Equal(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveMissingTask(t *testing.T) {
+	tl := newTestList("a", "b")
+	if err := moveUp(tl, 5); err == nil {
+		t.Error("moveUp: expected an error")
+	}
+	if err := moveDown(tl, 5); err == nil {
+		t.Error("moveDown: expected an error")
+	}
+}
+
+func TestSortTasksPersisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	oldPath := tasksPath
+	tasksPath = path
+	defer func() { tasksPath = oldPath }()
+
+	tl := newTestList("cherry", "apple", "Banana")
+	tl.Tasks[0].DueDate = "2024-06-05"
+	tl.Tasks[2].DueDate = "2024-06-02"
+
+	tests := []struct {
+		key  string
+		want []int
+	}{
+		{"content", []int{2, 3, 1}},
+		{"due", []int{3, 1, 2}},
+		{"id", []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		if err := sortTasks(tl.Tasks, tt.key); err != nil {
+			t.Fatal(err)
+		}
+		if err := saveTask(tl); err != nil {
+			t.Fatal(err)
+		}
+
+		saved, err := loadTodoFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := taskIds(saved.Tasks); !slices.Equal(got, tt.want) {
+			t.Errorf("on-disk order after sorting by %s = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestSortTasksInvalidKey(t *testing.T) {
+	tl := newTestList("b", "a")
+	if err := sortTasks(tl.Tasks, "color"); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+	if got := taskIds(tl.Tasks); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("order changed to %v", got)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	priorityHigh   = "high"
+	priorityMedium = "medium"
+	priorityLow    = "low"
+)
+
+// normalizePriority приводит уровень приоритета к нижнему регистру и проверяет его
+// Пустое значение означает приоритет по умолчанию
+func normalizePriority(level string) (string, error) {
+	level = strings.ToLower(strings.TrimSpace(level))
+	switch level {
+	case "", priorityHigh, priorityMedium, priorityLow:
+		return level, nil
+	default:
+		return "", fmt.Errorf(msg("Ошибка: не верный приоритет %q (ожидается high, medium или low)"), level)
+	}
+}
+
+// priorityRank возвращает порядковый номер приоритета: чем меньше, тем важнее
+// Задачи без приоритета считаются задачами среднего приоритета
+func priorityRank(level string) int {
+	switch level {
+	case priorityHigh:
+		return 0
+	case priorityLow:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// hasPriority проверяет, что задача имеет указанный уровень приоритета
+// Задачи без приоритета относятся к среднему уровню
+func hasPriority(task Task, level string) bool {
+	return priorityRank(task.Priority) == priorityRank(strings.ToLower(level))
+}
+
+// filterByPriority возвращает задачи с указанным уровнем приоритета (без учета регистра) в исходном порядке
+// Задачи без приоритета относятся к среднему уровню; уровень должен быть проверен через normalizePriority
+func filterByPriority(tasks []Task, level string) []Task {
+	var result []Task
+	for _, task := range tasks {
+		if hasPriority(task, level) {
+			result = append(result, task)
+		}
+	}
+	return result
+}
+
+// setPriority устанавливает или снимает приоритет задачи
+func setPriority(tl *TodoList, id int, level string) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	level, err := normalizePriority(level)
+	if err != nil {
+		return err
+	}
+
+	tl.Tasks[index].Priority = level
+	return nil
+}
+
+// setPriorityByTag устанавливает приоритет всем задачам с тегом (без учета регистра)
+// Возвращает количество задач, у которых приоритет изменился; пустой уровень снимает приоритет
+func setPriorityByTag(tl *TodoList, tag, level string) (int, error) {
+	level, err := normalizePriority(level)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for i := range tl.Tasks {
+		task := &tl.Tasks[i]
+		if !hasTag(*task, tag) || task.Priority == level {
+			continue
+		}
+
+		task.Priority = level
+		changed++
+	}
+	return changed, nil
+}
+
+// reorderByPriority упорядочивает задачи в списке от высокого приоритета к низкому
+// Сортировка стабильная: внутри одного уровня задачи сохраняют прежний порядок
+func reorderByPriority(tl *TodoList) {
+	sort.SliceStable(tl.Tasks, func(i, j int) bool {
+		return priorityRank(tl.Tasks[i].Priority) < priorityRank(tl.Tasks[j].Priority)
+	})
+}
+
+// escalateOverdue повышает до high приоритет всех просроченных невыполненных задач
+// Возвращает количество задач, у которых приоритет изменился
+func escalateOverdue(tl *TodoList, now time.Time) int {
+	changed := 0
+	for i := range tl.Tasks {
+		task := &tl.Tasks[i]
+		if task.Done || task.Priority == priorityHigh || !isOverdue(*task, now) {
+			continue
+		}
+
+		task.Priority = priorityHigh
+		recordEvent(task, eventEdit, now)
+		changed++
+	}
+	return changed
+}
+
+// readyTasks возвращает невыполненные задачи без невыполненных зависимостей,
+// отсортированные по приоритету, а внутри уровня — от старых к новым
+func readyTasks(tl *TodoList) []Task {
+	var tasks []Task
+	for _, task := range tl.Tasks {
+		if !task.Done && !isBlocked(tl, task) {
+			tasks = append(tasks, task)
+		}
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		ri, rj := priorityRank(tasks[i].Priority), priorityRank(tasks[j].Priority)
+		if ri != rj {
+			return ri < rj
+		}
+		return createdBefore(tasks[i], tasks[j])
+	})
+
+	return tasks
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestReadyTasks(t *testing.T) {
+	tl := newTestList("old low", "blocked high", "done", "new high", "unblocked", "medium")
+	tl.Tasks[0].Priority = priorityLow
+	tl.Tasks[1].Priority = priorityHigh
+	tl.Tasks[1].BlockedBy = []int{6}
+	markDone(&tl.Tasks[2], testNow)
+	tl.Tasks[3].Priority = priorityHigh
+	tl.Tasks[4].BlockedBy = []int{3}
+
+	// #2 ждёт невыполненную #6, #5 ждёт уже выполненную #3
+	want := []int{4, 5, 6, 1}
+	if got := taskIds(readyTasks(tl)); !slices.Equal(got, want) {
+		t.Errorf("ready = %v, want %v", got, want)
+	}
+
+	markDone(&tl.Tasks[5], testNow)
+	if got := taskIds(readyTasks(tl)); !slices.Equal(got, []int{2, 4, 5, 1}) {
+		t.Errorf("after completing #6 ready = %v, want [2 4 5 1]", got)
+	}
+}
+
+func TestFilterByPriority(t *testing.T) {
+	tl := newTestList("a", "b", "c", "d")
+	tl.Tasks[0].Priority = priorityHigh
+	tl.Tasks[1].Priority = priorityLow
+	tl.Tasks[2].Priority = priorityMedium
+
+	tests := []struct {
+		level string
+		want  []int
+	}{
+		{priorityHigh, []int{1}},
+		{priorityMedium, []int{3, 4}},
+		{priorityLow, []int{2}},
+		{"HIGH", []int{1}},
+	}
+
+	for _, tt := range tests {
+		if got := taskIds(filterByPriority(tl.Tasks, tt.level)); !slices.Equal(got, tt.want) {
+			t.Errorf("filterByPriority(%q) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestFilterPriorityWithStatus(t *testing.T) {
+	tl := newTestList("a", "b", "c", "d")
+	for i := range tl.Tasks {
+		tl.Tasks[i].Priority = priorityHigh
+	}
+	tl.Tasks[3].Priority = priorityLow
+	markDone(&tl.Tasks[1], testNow)
+
+	tests := []struct {
+		status string
+		want   []int
+	}{
+		{statusPending, []int{1, 3}},
+		{statusDone, []int{2}},
+		{"", []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		got := taskIds(applyFilters(tl, FilterOptions{Status: tt.status, Priority: priorityHigh}))
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("status %q: %v, want %v", tt.status, got, tt.want)
+		}
+		if got := taskIds(filterByPriority(applyFilters(tl, FilterOptions{Status: tt.status}), priorityHigh)); !slices.Equal(got, tt.want) {
+			t.Errorf("status %q via filterByPriority: %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizePriority(t *testing.T) {
+	for _, level := range []string{"urgent", "hi", "0"} {
+		if _, err := normalizePriority(level); err == nil {
+			t.Errorf("normalizePriority(%q): expected an error", level)
+		}
+	}
+	if got, err := normalizePriority(" High "); err != nil || got != priorityHigh {
+		t.Errorf("normalizePriority(\" High \") = %q, %v", got, err)
+	}
+}
+
+func TestEscalateOverdue(t *testing.T) {
+	tl := newTestList("overdue", "overdue high", "due today", "future", "no due", "overdue done", "overdue low")
+	tl.Tasks[0].DueDate = "2024-05-30"
+	tl.Tasks[1].DueDate = "2024-05-01"
+	tl.Tasks[1].Priority = priorityHigh
+	tl.Tasks[2].DueDate = "2024-06-01"
+	tl.Tasks[3].DueDate = "2024-06-02"
+	tl.Tasks[5].DueDate = "2024-05-01"
+	markDone(&tl.Tasks[5], testNow)
+	tl.Tasks[6].DueDate = "2024-05-31"
+	tl.Tasks[6].Priority = priorityLow
+
+	if got := escalateOverdue(tl, testNow); got != 2 {
+		t.Errorf("escalateOverdue = %d, want 2", got)
+	}
+
+	want := []string{priorityHigh, priorityHigh, "", "", "", "", priorityHigh}
+	for i, task := range tl.Tasks {
+		if task.Priority != want[i] {
+			t.Errorf("task #%d priority = %q, want %q", task.Id, task.Priority, want[i])
+		}
+	}
+
+	if got := escalateOverdue(tl, testNow); got != 0 {
+		t.Errorf("second escalateOverdue = %d, want 0", got)
+	}
+}
+
+func TestReorderByPriority(t *testing.T) {
+	tl := newTestList("low 1", "none 1", "high 1", "medium 1", "low 2", "high 2", "none 2")
+	levels := []string{priorityLow, "", priorityHigh, priorityMedium, priorityLow, priorityHigh, ""}
+	for i, level := range levels {
+		tl.Tasks[i].Priority = level
+	}
+
+	reorderByPriority(tl)
+
+	// Внутри уровня порядок сохраняется, задачи без приоритета идут вместе со средними
+	if got, want := taskIds(tl.Tasks), []int{3, 6, 2, 4, 7, 1, 5}; !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestSetPriorityByTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   string
+		want    int
+		wantErr bool
+		levels  []string
+	}{
+		{"raise to high", "HIGH", 2, false, []string{priorityHigh, priorityHigh, priorityHigh, ""}},
+		{"already at level unchanged", "low", 2, false, []string{priorityLow, priorityLow, priorityLow, ""}},
+		{"invalid level", "urgent", 0, true, []string{"", priorityLow, priorityHigh, ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("a", "b", "c", "d")
+			tl.Tasks[0].Tags = []string{"Project"}
+			tl.Tasks[1].Tags = []string{"project", "home"}
+			tl.Tasks[1].Priority = priorityLow
+			tl.Tasks[2].Tags = []string{"project"}
+			tl.Tasks[2].Priority = priorityHigh
+
+			got, err := setPriorityByTag(tl, "project", tt.level)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("setPriorityByTag = %d, %v; want %d, error %v", got, err, tt.want, tt.wantErr)
+			}
+			for i, task := range tl.Tasks {
+				if task.Priority != tt.levels[i] {
+					t.Errorf("task #%d priority = %q, want %q", task.Id, task.Priority, tt.levels[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultProfile = "tasks" // Имя профиля по умолчанию (файл tasks.json)
+
+// profilePath возвращает путь к файлу профиля в указанной директории
+func profilePath(baseDir, name string) string {
+	return filepath.Join(baseDir, name+".json")
+}
+
+// validateProfileName проверяет, что имя профиля можно использовать как имя файла
+func validateProfileName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New(msg("Ошибка: имя профиля не может быть пустым"))
+	}
+
+	if strings.ContainsAny(name, `/\`) {
+		return errors.New(msg("Ошибка: имя профиля не должно содержать разделители пути"))
+	}
+
+	return nil
+}
+
+// renameProfile переименовывает профиль, перемещая файл <old>.json в <new>.json
+// Отказывает, если профиль с новым именем уже существует
+func renameProfile(baseDir, oldName, newName string) error {
+	if err := validateProfileName(oldName); err != nil {
+		return err
+	}
+
+	if err := validateProfileName(newName); err != nil {
+		return err
+	}
+
+	oldPath := profilePath(baseDir, oldName)
+	newPath := profilePath(baseDir, newName)
+
+	if _, err := os.Stat(oldPath); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf(msg("Ошибка: профиль %s не найден"), oldName)
+		}
+
+		return err
+	}
+
+	if _, err := os.Stat(newPath); err == nil {
+		return fmt.Errorf(msg("Ошибка: профиль %s уже существует"), newName)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	return os.Rename(oldPath, newPath)
+}
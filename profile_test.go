@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRenameProfile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(profilePath(dir, "work"), []byte(`{"tasks":[],"next_id":1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := renameProfile(dir, "work", "job"); err != nil {
+		t.Fatalf("renameProfile: %v", err)
+	}
+
+	if _, err := os.Stat(profilePath(dir, "work")); !os.IsNotExist(err) {
+		t.Errorf("old profile still exists: %v", err)
+	}
+	if _, err := os.Stat(profilePath(dir, "job")); err != nil {
+		t.Errorf("new profile missing: %v", err)
+	}
+}
+
+func TestRenameProfileErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		oldName  string
+		newName  string
+	}{
+		{"collision", []string{"work", "job"}, "work", "job"},
+		{"missing", nil, "work", "job"},
+		{"empty new name", []string{"work"}, "work", " "},
+		{"path separator", []string{"work"}, "work", "../job"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tt.existing {
+				if err := os.WriteFile(profilePath(dir, name), []byte(name), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := renameProfile(dir, tt.oldName, tt.newName); err == nil {
+				t.Fatal("expected an error")
+			}
+
+			for _, name := range tt.existing {
+				data, err := os.ReadFile(profilePath(dir, name))
+				if err != nil || string(data) != name {
+					t.Errorf("profile %s changed: %q, %v", name, data, err)
+				}
+			}
+		})
+	}
+}
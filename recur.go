@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Периоды повторения задачи
+const (
+	recurDaily   = "daily"
+	recurWeekly  = "weekly"
+	recurMonthly = "monthly"
+)
+
+// normalizeRecur проверяет период повторения; пустое значение и none отключают повторение
+func normalizeRecur(period string) (string, error) {
+	period = strings.ToLower(strings.TrimSpace(period))
+	switch period {
+	case "", "none":
+		return "", nil
+	case recurDaily, recurWeekly, recurMonthly:
+		return period, nil
+	default:
+		return "", fmt.Errorf(msg("Ошибка: не верный период повторения %q (ожидается daily, weekly, monthly или none)"), period)
+	}
+}
+
+// setRecur устанавливает или отключает повторение задачи
+func setRecur(tl *TodoList, id int, period string) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	period, err := normalizeRecur(period)
+	if err != nil {
+		return err
+	}
+
+	tl.Tasks[index].Recur = period
+	return nil
+}
+
+// setRecurUntil устанавливает или снимает дату окончания повторения задачи
+func setRecurUntil(tl *TodoList, id int, until string, now time.Time) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	until = strings.TrimSpace(until)
+	if until == "" || strings.EqualFold(until, "none") {
+		tl.Tasks[index].RecurUntil = ""
+		return nil
+	}
+
+	t, err := parseDate(until, now)
+	if err != nil {
+		return err
+	}
+
+	tl.Tasks[index].RecurUntil = t.Format(dateLayout)
+	return nil
+}
+
+// nextOccurrence возвращает срок следующего повторения задачи
+// Отсчёт идёт от срока задачи, а если его нет — от сегодняшнего дня.
+// Возвращает false, если задача не повторяется или следующий срок позже RecurUntil
+func nextOccurrence(task Task, now time.Time) (time.Time, bool) {
+	base := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if task.DueDate != "" {
+		if due, err := time.ParseInLocation(dateLayout, task.DueDate, now.Location()); err == nil {
+			base = due
+		}
+	}
+
+	var next time.Time
+	switch task.Recur {
+	case recurDaily:
+		next = base.AddDate(0, 0, 1)
+	case recurWeekly:
+		next = base.AddDate(0, 0, 7)
+	case recurMonthly:
+		next = base.AddDate(0, 1, 0)
+	default:
+		return time.Time{}, false
+	}
+
+	if task.RecurUntil != "" {
+		until, err := time.ParseInLocation(dateLayout, task.RecurUntil, now.Location())
+		if err == nil && next.After(until) {
+			return time.Time{}, false
+		}
+	}
+
+	return next, true
+}
+
+// pastOccurrence проверяет, что задача — выполненное повторение, для которого уже создано следующее
+// Следующее повторение хранит тот же текст, поэтому прошлое не считается его дубликатом
+func pastOccurrence(task Task) bool {
+	return task.Done && task.Recurred
+}
+
+// regenerateRecurring добавляет следующее повторение выполненной задачи с указанным ID
+// Новая задача наследует текст, теги и настройки повторения и получает следующий срок.
+// Повторение создаётся один раз: если задачу снова отметить невыполненной и выполнить,
+// второго повторения не будет. После даты окончания повторения задача просто остаётся выполненной
+func regenerateRecurring(tl *TodoList, id int, now time.Time) (Task, bool) {
+	index := findTaskIndex(tl, id)
+	if index == -1 || !tl.Tasks[index].Done || tl.Tasks[index].Recurred {
+		return Task{}, false
+	}
+
+	source := tl.Tasks[index]
+	next, ok := nextOccurrence(source, now)
+	if !ok {
+		return Task{}, false
+	}
+
+	task := Task{
+		Id:         tl.NextId,
+		Content:    source.Content,
+		CreatedAt:  now.Format(timeLayout),
+		Tags:       append([]string(nil), source.Tags...),
+		DueDate:    next.Format(dateLayout),
+		CreatedBy:  source.CreatedBy,
+		Assignee:   source.Assignee,
+		Notes:      source.Notes,
+		Color:      source.Color,
+		Priority:   source.Priority,
+		Estimate:   source.Estimate,
+		Recur:      source.Recur,
+		RecurUntil: source.RecurUntil,
+	}
+	recordEvent(&task, eventAdd, now)
+
+	tl.Tasks[index].Recurred = true
+	tl.Tasks = append(tl.Tasks, task)
+	tl.NextId++
+	return task, true
+}
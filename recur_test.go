@@ -0,0 +1,133 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// newRecurringList создаёт список из одной ежедневной задачи со сроком на 2024-06-01
+func newRecurringList(until string) *TodoList {
+	tl := newTestList("Полить цветы")
+	tl.Tasks[0].Recur = recurDaily
+	tl.Tasks[0].DueDate = "2024-06-01"
+	tl.Tasks[0].RecurUntil = until
+	return tl
+}
+
+// dueDates возвращает сроки задач в порядке списка
+func dueDates(tl *TodoList) []string {
+	var dates []string
+	for _, task := range tl.Tasks {
+		dates = append(dates, task.DueDate)
+	}
+	return dates
+}
+
+func TestRegenerateUntilEndDate(t *testing.T) {
+	tl := newRecurringList("2024-06-03")
+
+	// Каждое следующее повторение выполняется, пока срок не выйдет за RecurUntil
+	for id := 1; id <= 3; id++ {
+		if err := completeTask(tl, id, false, testNow); err != nil {
+			t.Fatalf("completeTask(%d): %v", id, err)
+		}
+	}
+
+	if got, want := dueDates(tl), []string{"2024-06-01", "2024-06-02", "2024-06-03"}; !slices.Equal(got, want) {
+		t.Errorf("due dates = %v, want %v", got, want)
+	}
+	last := tl.Tasks[2]
+	if !last.Done || last.RecurUntil != "2024-06-03" || last.Recur != recurDaily {
+		t.Errorf("last occurrence = %+v", last)
+	}
+}
+
+func TestRegenerateWithoutEndDate(t *testing.T) {
+	tl := newRecurringList("")
+	if err := completeTask(tl, 1, false, testNow); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tl.Tasks) != 2 || tl.Tasks[1].DueDate != "2024-06-02" || tl.Tasks[1].Done || tl.NextId != 3 {
+		t.Errorf("tasks = %+v, NextId = %d", tl.Tasks, tl.NextId)
+	}
+}
+
+func TestToggleRegeneratesOnce(t *testing.T) {
+	tl := newRecurringList("")
+
+	for range 3 {
+		if _, err := toggleStatus(tl, 1, false, testNow); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// done → pending → done: повторение создано только при первом выполнении
+	if got := taskIds(tl.Tasks); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("ids = %v, want [1 2]", got)
+	}
+	if !tl.Tasks[0].Done || tl.Tasks[1].Done {
+		t.Errorf("statuses = %v, %v", tl.Tasks[0].Done, tl.Tasks[1].Done)
+	}
+}
+
+func TestCompletionCommandsRegenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		complete func(tl *TodoList)
+	}{
+		{"complete", func(tl *TodoList) { completeTask(tl, 1, false, testNow) }},
+		{"toggle", func(tl *TodoList) { toggleStatus(tl, 1, false, testNow) }},
+		{"complete last", func(tl *TodoList) { completeLastTask(tl, false, testNow) }},
+		{"complete oldest", func(tl *TodoList) { completeOldest(tl, 5, false, testNow) }},
+		{"complete all", func(tl *TodoList) { completeAllTasks(tl, func(Task) bool { return true }, false, testNow) }},
+		{"invert all", func(tl *TodoList) { invertAll(tl, false, testNow) }},
+		{"subtasks done", func(tl *TodoList) {
+			tl.Tasks[0].Subtasks = []Subtask{{Content: "полить", Done: true}}
+			syncParentCompletion(tl, false, testNow)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newRecurringList("")
+			tt.complete(tl)
+
+			if len(tl.Tasks) != 2 {
+				t.Fatalf("tasks = %v, want the task and one successor", taskIds(tl.Tasks))
+			}
+			if !tl.Tasks[0].Done || tl.Tasks[1].Done || tl.Tasks[1].DueDate != "2024-06-02" {
+				t.Errorf("tasks = %+v", tl.Tasks)
+			}
+		})
+	}
+}
+
+func TestPastOccurrenceIsNotDuplicate(t *testing.T) {
+	tl := newRecurringList("")
+	if err := completeTask(tl, 1, false, testNow); err != nil {
+		t.Fatal(err)
+	}
+
+	if removed, _ := dedupe(tl, false); removed != 0 {
+		t.Errorf("dedupe removed %d tasks, want 0", removed)
+	}
+	if got := taskIds(tl.Tasks); !slices.Equal(got, []int{1, 2}) || tl.Tasks[1].Done {
+		t.Errorf("tasks after dedupe = %+v", tl.Tasks)
+	}
+
+	if pairs := nearDuplicates(tl, 0); len(pairs) != 0 {
+		t.Errorf("nearDuplicates = %v, want none", pairs)
+	}
+	if got := findTaskByContent(tl, "полить цветы"); !slices.Equal(got, []int{1}) {
+		t.Errorf("findTaskByContent = %v, want the next occurrence only", got)
+	}
+	if err := editTask(tl, 2, "Полить цветы", testNow); err != nil {
+		t.Errorf("editTask on the next occurrence: %v", err)
+	}
+
+	// Текущее повторение по-прежнему защищено от дубликатов
+	if _, err := createTask(tl, "полить цветы", AddOptions{}, testNow); err == nil {
+		t.Error("createTask accepted a duplicate of the pending occurrence")
+	}
+}
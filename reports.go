@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"slices"
+	"sort"
+	"time"
+)
+
+// overdueTasks возвращает просроченные невыполненные задачи
+// Самые просроченные идут первыми, задачи без срока не учитываются
+func overdueTasks(tl *TodoList, now time.Time) []Task {
+	var tasks []Task
+	for _, task := range tl.Tasks {
+		if isOverdue(task, now) {
+			tasks = append(tasks, task)
+		}
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].DueDate < tasks[j].DueDate
+	})
+
+	return tasks
+}
+
+// listOverdue выводит просроченные задачи
+func listOverdue(tl *TodoList, now time.Time) {
+	tasks := overdueTasks(tl, now)
+	if len(tasks) == 0 {
+		fmt.Println(msg("Нет просроченных задач"))
+		return
+	}
+
+	fmt.Println(msg("Просроченные задачи:"))
+	for _, task := range tasks {
+		fmt.Printf(msg("%d, %s (срок: %s)\n"), task.Id, task.Content, task.DueDate)
+	}
+}
+
+// dueToday возвращает невыполненные задачи со сроком на день now
+func dueToday(tl *TodoList, now time.Time) []Task {
+	today := now.Format(dateLayout)
+	var tasks []Task
+	for _, task := range tl.Tasks {
+		if !task.Done && task.DueDate == today {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// completedToday возвращает задачи, выполненные в день now, по времени завершения
+func completedToday(tl *TodoList, now time.Time) []Task {
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return completedBetween(tl, from, from.AddDate(0, 0, 1).Add(-time.Second))
+}
+
+// writeDigestSection выводит раздел сводки с заголовком; пустой раздел отмечается «— нет —»
+func writeDigestSection(w io.Writer, title string, tasks []Task, line func(Task) string) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, title)
+	if len(tasks) == 0 {
+		fmt.Fprintln(w, msg("— нет —"))
+		return
+	}
+	for _, task := range tasks {
+		fmt.Fprintln(w, line(task))
+	}
+}
+
+// writeDigest выводит текстовую сводку за день now для письма:
+// выполненные сегодня, просроченные и задачи со сроком на сегодня
+func writeDigest(tl *TodoList, now time.Time, w io.Writer) {
+	plain := func(task Task) string {
+		return fmt.Sprintf("- #%d %s", task.Id, task.Content)
+	}
+
+	fmt.Fprintf(w, msg("Сводка задач на %s\n"), now.Format(dateLayout))
+	writeDigestSection(w, msg("Выполнено сегодня:"), completedToday(tl, now), plain)
+	writeDigestSection(w, msg("Просрочено:"), overdueTasks(tl, now), func(task Task) string {
+		return fmt.Sprintf(msg("- #%d %s (срок: %s)"), task.Id, task.Content, task.DueDate)
+	})
+	writeDigestSection(w, msg("Срок сегодня:"), dueToday(tl, now), plain)
+}
+
+// autoCloseOverdue отмечает выполненными просроченные невыполненные задачи с тегом (без учета регистра)
+// Выполнение идёт как в --complete, поэтому повторяющиеся задачи создают следующую.
+// Защищённые задачи без force пропускаются. Возвращает количество задач и ID пропущенных
+func autoCloseOverdue(tl *TodoList, tag string, force bool, now time.Time) (int, []int) {
+	var ids, skipped []int
+	for _, task := range tl.Tasks {
+		if !hasTag(task, tag) || !isOverdue(task, now) {
+			continue
+		}
+		if protected(task, force) {
+			skipped = append(skipped, task.Id)
+			continue
+		}
+		ids = append(ids, task.Id)
+	}
+
+	for _, id := range ids {
+		completeTask(tl, id, force, now)
+	}
+	return len(ids), skipped
+}
+
+// pickRandom выбирает случайную невыполненную задачу
+// Генератор передаётся явно, чтобы выбор можно было воспроизвести
+func pickRandom(r *rand.Rand, tl *TodoList) (*Task, bool) {
+	var pending []int
+	for i := range tl.Tasks {
+		if !tl.Tasks[i].Done {
+			pending = append(pending, i)
+		}
+	}
+
+	if len(pending) == 0 {
+		return nil, false
+	}
+
+	return &tl.Tasks[pending[r.Intn(len(pending))]], true
+}
+
+// completedBetween возвращает задачи, выполненные в промежутке от from до to включительно,
+// отсортированные по времени завершения
+func completedBetween(tl *TodoList, from, to time.Time) []Task {
+	var tasks []Task
+	for _, task := range tl.Tasks {
+		if !task.Done {
+			continue
+		}
+
+		completedAt, err := parseTime(task.CompletedAt)
+		if err != nil || completedAt.Before(from) || completedAt.After(to) {
+			continue
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].CompletedAt < tasks[j].CompletedAt
+	})
+
+	return tasks
+}
+
+// recentTasks возвращает n последних созданных задач, от новых к старым
+// Созданные в одну секунду задачи идут в обратном порядке списка, задачи с некорректной датой — в конце
+func recentTasks(tl *TodoList, n int) []Task {
+	tasks := slices.Clone(tl.Tasks)
+	slices.Reverse(tasks)
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		ti, errI := parseTime(tasks[i].CreatedAt)
+		tj, errJ := parseTime(tasks[j].CreatedAt)
+		if errI != nil {
+			return false
+		}
+		if errJ != nil {
+			return true
+		}
+		return ti.After(tj)
+	})
+
+	if n < len(tasks) {
+		tasks = tasks[:n]
+	}
+	return tasks
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOverdueTasks(t *testing.T) {
+	tl := &TodoList{Tasks: []Task{
+		{Id: 1, Content: "slightly late", DueDate: "2024-05-30"},
+		{Id: 2, Content: "no due"},
+		{Id: 3, Content: "very late", DueDate: "2024-01-15"},
+		{Id: 4, Content: "due today", DueDate: "2024-06-01"},
+		{Id: 5, Content: "done late", DueDate: "2024-01-01", Done: true},
+		{Id: 6, Content: "future", DueDate: "2024-07-01"},
+	}}
+
+	if got, want := taskIds(overdueTasks(tl, testNow)), []int{3, 1}; !slices.Equal(got, want) {
+		t.Errorf("overdueTasks = %v, want %v", got, want)
+	}
+}
+
+func TestOverdueTasksNone(t *testing.T) {
+	tl := newTestList("a")
+	if got := overdueTasks(tl, testNow); len(got) != 0 {
+		t.Errorf("overdueTasks = %v, want none", got)
+	}
+}
+
+func TestPickRandomFixedSeed(t *testing.T) {
+	tl := newTestList("a", "b", "c", "d")
+	tl.Tasks[1].Done = true
+
+	r := rand.New(rand.NewSource(42))
+	var got []int
+	for range 5 {
+		task, ok := pickRandom(r, tl)
+		if !ok {
+			t.Fatal("no task picked")
+		}
+		got = append(got, task.Id)
+	}
+
+	if want := []int{4, 4, 4, 1, 3}; !slices.Equal(got, want) {
+		t.Errorf("picked %v, want %v", got, want)
+	}
+}
+
+func TestPickRandomNothingPending(t *testing.T) {
+	tl := newTestList("a")
+	tl.Tasks[0].Done = true
+
+	if task, ok := pickRandom(rand.New(rand.NewSource(1)), tl); ok {
+		t.Errorf("pickRandom = %v, want none", task)
+	}
+}
+
+func TestCompletedBetween(t *testing.T) {
+	tl := &TodoList{Tasks: []Task{
+		{Id: 1, Done: true, CompletedAt: "2024-06-03 09:00:00"},
+		{Id: 2, Done: true, CompletedAt: "2024-06-01 00:00:00"},
+		{Id: 3, Done: true, CompletedAt: "2024-05-31 23:59:59"},
+		{Id: 4, Done: true, CompletedAt: "2024-06-03 23:59:59"},
+		{Id: 5, Done: true, CompletedAt: "2024-06-04 00:00:00"},
+		{Id: 6, Done: false},
+		{Id: 7, Done: true, CompletedAt: "broken"},
+	}}
+
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2024, 6, 3, 23, 59, 59, 0, time.Local)
+
+	if got, want := taskIds(completedBetween(tl, from, to)), []int{2, 1, 4}; !slices.Equal(got, want) {
+		t.Errorf("completedBetween = %v, want %v", got, want)
+	}
+	if got := completedBetween(tl, to, from); len(got) != 0 {
+		t.Errorf("reversed range = %v, want none", taskIds(got))
+	}
+}
+
+func TestRecentTasks(t *testing.T) {
+	tl := newTestList("old", "newest", "broken", "middle", "same second")
+	tl.Tasks[0].CreatedAt = "2024-05-01 10:00:00"
+	tl.Tasks[1].CreatedAt = "2024-06-01 10:00:00"
+	tl.Tasks[2].CreatedAt = "yesterday"
+	tl.Tasks[3].CreatedAt = "2024-05-15 10:00:00"
+	tl.Tasks[4].CreatedAt = "2024-05-15 10:00:00"
+
+	tests := []struct {
+		n    int
+		want []int
+	}{
+		{2, []int{2, 5}},
+		{4, []int{2, 5, 4, 1}},
+		{10, []int{2, 5, 4, 1, 3}},
+		{0, []int{}},
+	}
+
+	for _, tt := range tests {
+		if got := taskIds(recentTasks(tl, tt.n)); !slices.Equal(got, tt.want) {
+			t.Errorf("recentTasks(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+	if got := taskIds(tl.Tasks); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("list reordered: %v", got)
+	}
+}
+
+func TestWriteDigest(t *testing.T) {
+	tl := &TodoList{Tasks: []Task{
+		{Id: 1, Content: "report", Done: true, CompletedAt: "2024-06-01 09:30:00"},
+		{Id: 2, Content: "old report", Done: true, CompletedAt: "2024-05-31 18:00:00"},
+		{Id: 3, Content: "taxes", DueDate: "2024-05-20"},
+		{Id: 4, Content: "call mom", DueDate: "2024-06-01"},
+		{Id: 5, Content: "later", DueDate: "2024-06-10"},
+	}}
+
+	var buf bytes.Buffer
+	writeDigest(tl, testNow, &buf)
+
+	want := "Сводка задач на 2024-06-01\n" +
+		"\nВыполнено сегодня:\n- #1 report\n" +
+		"\nПросрочено:\n- #3 taxes (срок: 2024-05-20)\n" +
+		"\nСрок сегодня:\n- #4 call mom\n"
+	if buf.String() != want {
+		t.Errorf("digest =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteDigestEmptySections(t *testing.T) {
+	var buf bytes.Buffer
+	writeDigest(&TodoList{Tasks: []Task{{Id: 1, Content: "later", DueDate: "2024-06-10"}}}, testNow, &buf)
+
+	if got := strings.Count(buf.String(), "— нет —"); got != 3 {
+		t.Errorf("empty sections = %d, want 3:\n%s", got, buf.String())
+	}
+}
+
+func TestAutoCloseOverdue(t *testing.T) {
+	tl := &TodoList{Tasks: []Task{
+		{Id: 1, Content: "overdue tagged", DueDate: "2024-05-30", Tags: []string{"Auto-Close"}},
+		{Id: 2, Content: "overdue untagged", DueDate: "2024-05-30"},
+		{Id: 3, Content: "future tagged", DueDate: "2024-06-05", Tags: []string{"auto-close"}},
+		{Id: 4, Content: "today tagged", DueDate: "2024-06-01", Tags: []string{"auto-close"}},
+		{Id: 5, Content: "no due tagged", Tags: []string{"auto-close"}},
+	}, NextId: 6}
+
+	if got, _ := autoCloseOverdue(tl, "auto-close", false, testNow); got != 1 {
+		t.Errorf("autoCloseOverdue = %d, want 1", got)
+	}
+	for _, task := range tl.Tasks {
+		if want := task.Id == 1; task.Done != want {
+			t.Errorf("task #%d done = %v, want %v", task.Id, task.Done, want)
+		}
+	}
+	if tl.Tasks[0].CompletedAt != testNow.Format(timeLayout) {
+		t.Errorf("CompletedAt = %q", tl.Tasks[0].CompletedAt)
+	}
+}
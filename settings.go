@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Источники значений настроек
+// Файла настроек пока нет, поэтому значение может прийти только из флага, окружения или по умолчанию
+const (
+	sourceFlag    = "flag"
+	sourceEnv     = "env"
+	sourceDefault = "default"
+)
+
+// Setting описывает действующее значение настройки и его источник
+type Setting struct {
+	Name   string // Название настройки
+	Value  string // Действующее значение
+	Source string // Откуда взято значение: flag, env или default
+}
+
+// SettingsInput содержит исходные данные для определения действующих настроек
+type SettingsInput struct {
+	SetFlags   map[string]bool // Флаги, явно указанные в командной строке
+	Profile    string          // Значение --profile
+	Lang       string          // Выбранный язык сообщений
+	Sort       string          // Значение --sort
+	TimeFormat string          // Значение --time-format
+}
+
+// resolveSettings определяет действующие настройки и источник каждой из них
+func resolveSettings(in SettingsInput, getenv func(string) string) []Setting {
+	pick := func(flagName, envName string) string {
+		switch {
+		case in.SetFlags[flagName]:
+			return sourceFlag
+		case envName != "" && getenv(envName) != "":
+			return sourceEnv
+		default:
+			return sourceDefault
+		}
+	}
+
+	sort := in.Sort
+	if sort == "" {
+		sort = "none"
+	}
+	timeFormat := in.TimeFormat
+	if timeFormat == "" {
+		timeFormat = timeLayout
+	}
+	timezone := getenv("TZ")
+	if timezone == "" {
+		timezone, _ = time.Now().Zone()
+	}
+
+	return []Setting{
+		{Name: "file", Value: profilePath(".", in.Profile), Source: pick("profile", "")},
+		{Name: "lang", Value: in.Lang, Source: pick("lang", "LANG")},
+		{Name: "timezone", Value: timezone, Source: pick("", "TZ")},
+		{Name: "sort", Value: sort, Source: pick("sort", "")},
+		{Name: "time-format", Value: timeFormat, Source: pick("time-format", "")},
+	}
+}
+
+// printSettings выводит настройки в виде "имя = значение (источник)"
+func printSettings(settings []Setting, w io.Writer) {
+	for _, s := range settings {
+		fmt.Fprintf(w, "%s = %s (%s)\n", s.Name, s.Value, s.Source)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestResolveSettingsSources(t *testing.T) {
+	env := map[string]string{"LANG": "en_US.UTF-8", "TZ": "Europe/Moscow"}
+	in := SettingsInput{
+		SetFlags:   map[string]bool{"profile": true, "sort": true},
+		Profile:    "work",
+		Lang:       langEnglish,
+		Sort:       "due",
+		TimeFormat: "",
+	}
+
+	settings := resolveSettings(in, func(key string) string { return env[key] })
+
+	want := []Setting{
+		{Name: "file", Value: profilePath(".", "work"), Source: sourceFlag},
+		{Name: "lang", Value: langEnglish, Source: sourceEnv},
+		{Name: "timezone", Value: "Europe/Moscow", Source: sourceEnv},
+		{Name: "sort", Value: "due", Source: sourceFlag},
+		{Name: "time-format", Value: timeLayout, Source: sourceDefault},
+	}
+	if len(settings) != len(want) {
+		t.Fatalf("settings = %+v", settings)
+	}
+	for i := range want {
+		if settings[i] != want[i] {
+			t.Errorf("setting %d = %+v, want %+v", i, settings[i], want[i])
+		}
+	}
+}
+
+func TestResolveSettingsFlagBeatsEnv(t *testing.T) {
+	in := SettingsInput{SetFlags: map[string]bool{"lang": true}, Profile: defaultProfile, Lang: langRussian}
+	settings := resolveSettings(in, func(key string) string {
+		if key == "LANG" {
+			return "en_US.UTF-8"
+		}
+		return ""
+	})
+
+	if s := settings[1]; s.Name != "lang" || s.Source != sourceFlag {
+		t.Errorf("lang setting = %+v, want source flag", s)
+	}
+	if s := settings[3]; s.Value != "none" || s.Source != sourceDefault {
+		t.Errorf("sort setting = %+v", s)
+	}
+}
+
+func TestPrintSettings(t *testing.T) {
+	var buf bytes.Buffer
+	printSettings([]Setting{{Name: "sort", Value: "due", Source: sourceFlag}}, &buf)
+	if buf.String() != "sort = due (flag)\n" {
+		t.Errorf("output = %q", buf.String())
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// showTask выводит подробную информацию о задаче и её чек-лист
+// Дата и время выводятся в формате layout (пустой — формат хранения)
+func showTask(task Task, w io.Writer, layout string) {
+	status := msg("не выполнено")
+	if task.Done {
+		status = msg("выполнено")
+	}
+
+	fmt.Fprintf(w, msg("Задача #%d\n"), task.Id)
+	fmt.Fprintf(w, msg("Текст: %s\n"), task.Content)
+	fmt.Fprintf(w, msg("Статус: %s\n"), status)
+	fmt.Fprintf(w, msg("Создана: %s\n"), displayTime(task.CreatedAt, layout))
+	if task.CreatedBy != "" {
+		fmt.Fprintf(w, msg("Автор: %s\n"), task.CreatedBy)
+	}
+	if task.Assignee != "" {
+		fmt.Fprintf(w, msg("Исполнитель: %s\n"), task.Assignee)
+	}
+	if task.Done && task.CompletedAt != "" {
+		fmt.Fprintf(w, msg("Выполнена: %s\n"), displayTime(task.CompletedAt, layout))
+	}
+	if task.Priority != "" {
+		fmt.Fprintf(w, msg("Приоритет: %s\n"), task.Priority)
+	}
+	if task.Estimate != 0 {
+		fmt.Fprintf(w, msg("Оценка: %g ч.\n"), task.Estimate)
+	}
+	if task.Color != "" {
+		fmt.Fprintf(w, msg("Цвет: %s\n"), task.Color)
+	}
+	if task.DueDate != "" {
+		fmt.Fprintf(w, msg("Срок: %s\n"), task.DueDate)
+	}
+	if task.Recur != "" {
+		fmt.Fprintf(w, msg("Повторение: %s\n"), task.Recur)
+	}
+	if task.RecurUntil != "" {
+		fmt.Fprintf(w, msg("Повторять до: %s\n"), task.RecurUntil)
+	}
+	if len(task.BlockedBy) > 0 {
+		fmt.Fprintf(w, msg("Зависит от: %s\n"), formatIds(task.BlockedBy))
+	}
+	if task.Locked {
+		fmt.Fprintln(w, msg("Защищена от удаления и изменения статуса"))
+	}
+	if len(task.Tags) > 0 {
+		fmt.Fprintf(w, msg("Теги: %s\n"), strings.Join(task.Tags, ", "))
+	}
+
+	if task.Notes != "" {
+		fmt.Fprintf(w, msg("Заметки: %s\n"), task.Notes)
+	}
+
+	if len(task.Subtasks) > 0 {
+		fmt.Fprintln(w, msg("Подзадачи:"))
+		for i, s := range task.Subtasks {
+			mark := " "
+			if s.Done {
+				mark = "x"
+			}
+			fmt.Fprintf(w, "  %d [%s] %s\n", i+1, mark, s.Content)
+		}
+	}
+}
+
+// focusTask выводит только одну задачу с подробностями и прогрессом подзадач
+func focusTask(tl *TodoList, id int, w io.Writer, layout string) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	task := tl.Tasks[index]
+	showTask(task, w, layout)
+	if done, total := subtaskProgress(task); total > 0 {
+		fmt.Fprintf(w, msg("Выполнено подзадач: %d из %d\n"), done, total)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFocusTaskWithSubtasks(t *testing.T) {
+	tl := newTestList("other", "Ремонт")
+	tl.Tasks[1].Subtasks = []Subtask{{Content: "Купить краску", Done: true}, {Content: "Покрасить"}}
+
+	var buf bytes.Buffer
+	if err := focusTask(tl, 2, &buf, ""); err != nil {
+		t.Fatalf("focusTask: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"Задача #2\n",
+		"Текст: Ремонт\n",
+		"Подзадачи:\n",
+		"  1 [x] Купить краску\n",
+		"  2 [ ] Покрасить\n",
+		"Выполнено подзадач: 1 из 2\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "other") {
+		t.Errorf("output mentions another task:\n%s", out)
+	}
+}
+
+func TestFocusTaskNotFound(t *testing.T) {
+	var buf bytes.Buffer
+	err := focusTask(newTestList("a"), 5, &buf, "")
+	if err == nil || err.Error() != "Задача не найдена" {
+		t.Errorf("focusTask error = %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
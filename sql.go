@@ -0,0 +1,106 @@
+package main
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	_ "modernc.org/sqlite" // Драйвер SQLite на чистом Go
+)
+
+// sqlSchema — схема таблицы tasks, столбцы соответствуют полям Task
+// Теги и зависимости хранятся строкой через запятую, подзадачи и история не выгружаются
+const sqlSchema = `CREATE TABLE tasks (
+  id INTEGER PRIMARY KEY,
+  content TEXT NOT NULL,
+  done INTEGER NOT NULL,
+  created_at TEXT,
+  completed_at TEXT,
+  tags TEXT,
+  due_date TEXT,
+  created_by TEXT,
+  notes TEXT,
+  color TEXT,
+  blocked_by TEXT,
+  priority TEXT,
+  estimate REAL,
+  recur TEXT,
+  recur_until TEXT,
+  assignee TEXT
+)`
+
+// sqlInsert добавляет одну задачу в таблицу tasks
+const sqlInsert = `INSERT INTO tasks VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+// sqlNull возвращает значение столбца, пустая строка становится NULL
+func sqlNull(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// joinIds склеивает ID через запятую
+func joinIds(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ",")
+}
+
+// exportSQLite записывает задачи в базу SQLite по пути path
+// Если файла нет, он создаётся; существующая таблица tasks заменяется, остальные таблицы не меняются
+// Запись выполняется в одной транзакции, поэтому при ошибке база остаётся прежней
+func exportSQLite(tl *TodoList, path string) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DROP TABLE IF EXISTS tasks"); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(sqlSchema); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(sqlInsert)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, task := range tl.Tasks {
+		_, err := stmt.Exec(
+			task.Id,
+			task.Content,
+			task.Done,
+			sqlNull(task.CreatedAt),
+			sqlNull(task.CompletedAt),
+			sqlNull(strings.Join(task.Tags, ",")),
+			sqlNull(task.DueDate),
+			sqlNull(task.CreatedBy),
+			sqlNull(task.Notes),
+			sqlNull(task.Color),
+			sqlNull(joinIds(task.BlockedBy)),
+			sqlNull(task.Priority),
+			task.Estimate,
+			sqlNull(task.Recur),
+			sqlNull(task.RecurUntil),
+			sqlNull(task.Assignee),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportSQLiteRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+	tl := newTestList("it's done", "pending")
+	markDone(&tl.Tasks[0], testNow)
+	tl.Tasks[1].Tags = []string{"work", "home"}
+	tl.Tasks[1].BlockedBy = []int{1}
+
+	if err := exportSQLite(tl, path); err != nil {
+		t.Fatalf("exportSQLite: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM tasks").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("rows = %d, want 2", count)
+	}
+
+	var content, tags, blockedBy string
+	var done bool
+	var dueDate sql.NullString
+	row := db.QueryRow("SELECT content, done, tags, blocked_by, due_date FROM tasks WHERE id = 2")
+	if err := row.Scan(&content, &done, &tags, &blockedBy, &dueDate); err != nil {
+		t.Fatal(err)
+	}
+	if content != "pending" || done || tags != "work,home" || blockedBy != "1" || dueDate.Valid {
+		t.Errorf("row 2 = %q, %v, %q, %q, %v", content, done, tags, blockedBy, dueDate)
+	}
+
+	if err := db.QueryRow("SELECT content FROM tasks WHERE done = 1").Scan(&content); err != nil || content != "it's done" {
+		t.Errorf("done row = %q, %v", content, err)
+	}
+}
+
+func TestExportSQLiteReplacesTable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+	if err := exportSQLite(newTestList("a", "b", "c"), path); err != nil {
+		t.Fatal(err)
+	}
+	if err := exportSQLite(newTestList("d"), path); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM tasks").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("rows after second export = %d, want 1", count)
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+const defaultBarWidth = 20 // Ширина индикатора выполнения по умолчанию
+
+// Stats содержит сводную статистику по списку задач
+type Stats struct {
+	Total   int     // Общее количество задач
+	Done    int     // Количество выполненных задач
+	Pending int     // Количество невыполненных задач
+	Overdue int     // Количество просроченных задач
+	Percent float64 // Процент выполненных задач (0 для пустого списка)
+}
+
+// isOverdue проверяет, просрочена ли невыполненная задача на момент now
+// Задача просрочена, если день её срока уже прошёл
+func isOverdue(task Task, now time.Time) bool {
+	if task.Done || task.DueDate == "" {
+		return false
+	}
+
+	due, err := time.ParseInLocation(dateLayout, task.DueDate, now.Location())
+	if err != nil {
+		return false
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return due.Before(today)
+}
+
+// computeStats подсчитывает статистику по списку задач на момент now
+func computeStats(tl *TodoList, now time.Time) Stats {
+	var s Stats
+	for _, task := range tl.Tasks {
+		s.Total++
+		if task.Done {
+			s.Done++
+		} else {
+			s.Pending++
+		}
+
+		if isOverdue(task, now) {
+			s.Overdue++
+		}
+	}
+
+	if s.Total > 0 {
+		s.Percent = float64(s.Done) / float64(s.Total) * 100
+	}
+
+	return s
+}
+
+// renderBar строит текстовый индикатор выполнения вида [#####-----] 50%
+// Значения процента за пределами 0–100 ограничиваются, ширина меньше нуля считается нулевой
+func renderBar(percent float64, width int) string {
+	if percent < 0 {
+		percent = 0
+	}
+	if percent > 100 {
+		percent = 100
+	}
+	if width < 0 {
+		width = 0
+	}
+
+	filled := int(percent/100*float64(width) + 0.5)
+	return fmt.Sprintf("[%s%s] %.0f%%", strings.Repeat("#", filled), strings.Repeat("-", width-filled), percent)
+}
+
+// printStats выводит статистику по задачам вместе с индикатором выполнения
+func printStats(tl *TodoList, width int, now time.Time) {
+	s := computeStats(tl, now)
+	fmt.Println(msg("Статистика задач:"))
+	fmt.Printf(msg("Всего: %d\n"), s.Total)
+	fmt.Printf(msg("Выполнено: %d\n"), s.Done)
+	fmt.Printf(msg("Не выполнено: %d\n"), s.Pending)
+	fmt.Printf(msg("Просрочено: %d\n"), s.Overdue)
+	fmt.Println(renderBar(s.Percent, width))
+}
+
+// statsJSON выводит статистику по задачам одной строкой JSON
+// Процент округляется до сотых; для пустого списка все значения нулевые
+func statsJSON(tl *TodoList, now time.Time, w io.Writer) error {
+	s := computeStats(tl, now)
+	data, err := encodeJSON(struct {
+		Total   int     `json:"total"`
+		Done    int     `json:"done"`
+		Pending int     `json:"pending"`
+		Percent float64 `json:"percent"`
+		Overdue int     `json:"overdue"`
+	}{s.Total, s.Done, s.Pending, math.Round(s.Percent*100) / 100, s.Overdue}, true)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// checkCompletion проверяет, что процент выполненных задач не ниже порога min
+// Пустой список считается выполненным на 100%, так как в нём нечего делать
+func checkCompletion(s Stats, min float64) error {
+	percent := s.Percent
+	if s.Total == 0 {
+		percent = 100
+	}
+
+	if percent < min {
+		return fmt.Errorf(msg("Ошибка: выполнено %.0f%% задач, что ниже порога %.0f%%"), percent, min)
+	}
+
+	return nil
+}
+
+// compactSummary возвращает однострочную сводку вида "3 pending, 1 overdue"
+// Количество просроченных задач выводится, только если оно больше нуля
+func compactSummary(tl *TodoList, now time.Time) string {
+	s := computeStats(tl, now)
+	line := fmt.Sprintf("%d pending", s.Pending)
+	if s.Overdue > 0 {
+		line += fmt.Sprintf(", %d overdue", s.Overdue)
+	}
+
+	return line
+}
+
+// completionStreak считает количество дней подряд, заканчивая сегодняшним,
+// в каждый из которых была выполнена хотя бы одна задача
+// Если сегодня ничего не выполнено, серия равна нулю
+func completionStreak(tl *TodoList, now time.Time) int {
+	days := make(map[string]bool)
+	for _, task := range tl.Tasks {
+		if !task.Done {
+			continue
+		}
+
+		completedAt, err := parseTime(task.CompletedAt)
+		if err != nil {
+			continue
+		}
+		days[completedAt.Format(dateLayout)] = true
+	}
+
+	streak := 0
+	for day := now; days[day.Format(dateLayout)]; day = day.AddDate(0, 0, -1) {
+		streak++
+	}
+
+	return streak
+}
+
+// completionsByHour считает выполненные задачи по часу выполнения (0–23)
+// Задачи без корректного времени выполнения не учитываются
+func completionsByHour(tl *TodoList) [24]int {
+	var hours [24]int
+	for _, task := range tl.Tasks {
+		if !task.Done {
+			continue
+		}
+
+		completedAt, err := parseTime(task.CompletedAt)
+		if err != nil {
+			continue
+		}
+		hours[completedAt.Hour()]++
+	}
+	return hours
+}
+
+// printCompletionsByHour выводит количество выполненных задач по часам с текстовой диаграммой
+// Самый загруженный час занимает width символов; при width <= 0 диаграмма не выводится
+func printCompletionsByHour(tl *TodoList, width int, w io.Writer) {
+	if width < 0 {
+		width = 0
+	}
+
+	hours := completionsByHour(tl)
+	peak := slices.Max(hours[:])
+	if peak == 0 {
+		fmt.Fprintln(w, msg("Нет выполненных задач"))
+		return
+	}
+
+	fmt.Fprintln(w, msg("Выполнено задач по часам:"))
+	for hour, count := range hours {
+		line := fmt.Sprintf("%02d: %3d %s", hour, count, strings.Repeat("#", (count*width+peak-1)/peak))
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+}
+
+// wordCount считает слова в тексте задач, разделяя их пробельными символами Unicode
+// Возвращает общее число слов и число слов по ID задачи
+func wordCount(tasks []Task) (int, map[int]int) {
+	total := 0
+	perTask := make(map[int]int, len(tasks))
+	for _, task := range tasks {
+		n := len(strings.Fields(task.Content))
+		perTask[task.Id] = n
+		total += n
+	}
+	return total, perTask
+}
+
+// printWordCount выводит число задач, общее и среднее число слов
+func printWordCount(tasks []Task, w io.Writer) {
+	total, _ := wordCount(tasks)
+	average := 0.0
+	if len(tasks) > 0 {
+		average = float64(total) / float64(len(tasks))
+	}
+
+	fmt.Fprintf(w, msg("Задач: %d\n"), len(tasks))
+	fmt.Fprintf(w, msg("Слов: %d\n"), total)
+	fmt.Fprintf(w, msg("В среднем слов на задачу: %.1f\n"), average)
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenderBar(t *testing.T) {
+	tests := []struct {
+		percent float64
+		width   int
+		want    string
+	}{
+		{0, 10, "[----------] 0%"},
+		{50, 10, "[#####-----] 50%"},
+		{100, 10, "[##########] 100%"},
+		{50, 0, "[] 50%"},
+		{50, -5, "[] 50%"},
+		{150, 4, "[####] 100%"},
+		{-10, 4, "[----] 0%"},
+	}
+
+	for _, tt := range tests {
+		if got := renderBar(tt.percent, tt.width); got != tt.want {
+			t.Errorf("renderBar(%v, %d) = %q, want %q", tt.percent, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestComputeStatsEmpty(t *testing.T) {
+	s := computeStats(&TodoList{}, testNow)
+	if s != (Stats{}) {
+		t.Errorf("computeStats(empty) = %+v", s)
+	}
+	if got := renderBar(s.Percent, defaultBarWidth); got != "[--------------------] 0%" {
+		t.Errorf("renderBar for empty list = %q", got)
+	}
+}
+
+func TestCompactSummary(t *testing.T) {
+	tests := []struct {
+		name  string
+		tasks []Task
+		want  string
+	}{
+		{"empty", nil, "0 pending"},
+		{"no overdue", []Task{{Id: 1}, {Id: 2, Done: true}}, "1 pending"},
+		{"overdue", []Task{
+			{Id: 1, DueDate: "2024-05-01"},
+			{Id: 2, DueDate: "2024-06-01"},
+			{Id: 3},
+			{Id: 4, Done: true, DueDate: "2024-05-01"},
+		}, "3 pending, 1 overdue"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compactSummary(&TodoList{Tasks: tt.tasks}, testNow); got != tt.want {
+				t.Errorf("compactSummary = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCompletion(t *testing.T) {
+	tests := []struct {
+		name    string
+		stats   Stats
+		min     float64
+		wantErr bool
+	}{
+		{"above", Stats{Total: 4, Done: 3, Percent: 75}, 50, false},
+		{"equal", Stats{Total: 2, Done: 1, Percent: 50}, 50, false},
+		{"below", Stats{Total: 4, Done: 1, Percent: 25}, 50, true},
+		{"empty list counts as 100%", Stats{}, 100, false},
+		{"no threshold", Stats{Total: 1}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkCompletion(tt.stats, tt.min); (err != nil) != tt.wantErr {
+				t.Errorf("checkCompletion error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckCompletionUsesComputeStats(t *testing.T) {
+	tl := newTestList("a", "b", "c", "d")
+	tl.Tasks[0].Done = true
+
+	if err := checkCompletion(computeStats(tl, testNow), 30); err == nil {
+		t.Error("25% passed a 30% threshold")
+	}
+	if err := checkCompletion(computeStats(tl, testNow), 25); err != nil {
+		t.Errorf("25%% failed a 25%% threshold: %v", err)
+	}
+}
+
+func TestCompletionStreak(t *testing.T) {
+	day := func(offset int) string {
+		return testNow.AddDate(0, 0, offset).Format(timeLayout)
+	}
+
+	tests := []struct {
+		name      string
+		completed []string
+		want      int
+	}{
+		{"three days", []string{day(0), day(-1), day(-2)}, 3},
+		{"several per day", []string{day(0), day(0), day(-1)}, 2},
+		{"gap breaks the streak", []string{day(0), day(-2), day(-3)}, 1},
+		{"nothing today", []string{day(-1), day(-2)}, 0},
+		{"empty", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := &TodoList{}
+			for i, at := range tt.completed {
+				tl.Tasks = append(tl.Tasks, Task{Id: i + 1, Done: true, CompletedAt: at})
+			}
+			// Невыполненная задача с датой завершения не учитывается
+			tl.Tasks = append(tl.Tasks, Task{Id: 99, CompletedAt: day(-1)})
+
+			if got := completionStreak(tl, testNow); got != tt.want {
+				t.Errorf("completionStreak = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompletionsByHour(t *testing.T) {
+	tl := &TodoList{Tasks: []Task{
+		{Id: 1, Done: true, CompletedAt: "2024-06-01 09:15:00"},
+		{Id: 2, Done: true, CompletedAt: "2024-05-20 09:59:59"},
+		{Id: 3, Done: true, CompletedAt: "2024-06-01 00:00:00"},
+		{Id: 4, Done: true, CompletedAt: "2024-06-01 23:30:00"},
+		{Id: 5, Done: false, CompletedAt: "2024-06-01 12:00:00"},
+		{Id: 6, Done: true, CompletedAt: "broken"},
+		{Id: 7},
+	}}
+
+	var want [24]int
+	want[0], want[9], want[23] = 1, 2, 1
+	if got := completionsByHour(tl); got != want {
+		t.Errorf("completionsByHour = %v, want %v", got, want)
+	}
+}
+
+func TestPrintCompletionsByHour(t *testing.T) {
+	tl := &TodoList{Tasks: []Task{
+		{Id: 1, Done: true, CompletedAt: "2024-06-01 09:15:00"},
+		{Id: 2, Done: true, CompletedAt: "2024-06-01 09:45:00"},
+		{Id: 3, Done: true, CompletedAt: "2024-06-01 14:00:00"},
+	}}
+
+	tests := []struct {
+		width    int
+		nine     string
+		fourteen string
+	}{
+		{10, "09:   2 ##########", "14:   1 #####"},
+		{0, "09:   2", "14:   1"},
+		{-3, "09:   2", "14:   1"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		printCompletionsByHour(tl, tt.width, &buf)
+
+		lines := strings.Split(buf.String(), "\n")
+		if len(lines) != 26 || lines[10] != tt.nine || lines[15] != tt.fourteen {
+			t.Errorf("width %d output:\n%s", tt.width, buf.String())
+		}
+	}
+}
+
+func TestWordCount(t *testing.T) {
+	tasks := []Task{
+		{Id: 1, Content: "Купить молоко и хлеб"},
+		{Id: 2, Content: "  Позвонить\tмаме  "},
+		{Id: 3, Content: ""},
+		{Id: 4, Content: "Ёлка в лесу"},
+	}
+
+	total, perTask := wordCount(tasks)
+
+	if total != 9 {
+		t.Errorf("total = %d, want 9", total)
+	}
+	want := map[int]int{1: 4, 2: 2, 3: 0, 4: 3}
+	for id, n := range want {
+		if perTask[id] != n {
+			t.Errorf("task #%d words = %d, want %d", id, perTask[id], n)
+		}
+	}
+}
+
+func TestPrintWordCount(t *testing.T) {
+	var buf bytes.Buffer
+	printWordCount([]Task{{Id: 1, Content: "один два три"}, {Id: 2, Content: "четыре"}}, &buf)
+	if want := "Задач: 2\nСлов: 4\nВ среднем слов на задачу: 2.0\n"; buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	printWordCount(nil, &buf)
+	if !strings.Contains(buf.String(), "В среднем слов на задачу: 0.0") {
+		t.Errorf("empty output = %q", buf.String())
+	}
+}
+
+func TestStatsJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		tasks []Task
+		want  string
+	}{
+		{"empty", nil, `{"total":0,"done":0,"pending":0,"percent":0,"overdue":0}`},
+		{"known list", []Task{
+			{Id: 1, Done: true},
+			{Id: 2, DueDate: "2024-05-01"},
+			{Id: 3, DueDate: "2024-06-05"},
+		}, `{"total":3,"done":1,"pending":2,"percent":33.33,"overdue":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := statsJSON(&TodoList{Tasks: tt.tasks}, testNow, &buf); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != tt.want {
+				t.Errorf("statsJSON = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Subtask представляет собой пункт чек-листа задачи
+type Subtask struct {
+	Content string `json:"content"` // Текст подзадачи
+	Done    bool   `json:"done"`    // Статус выполнения
+}
+
+// addSubtask добавляет подзадачу к задаче с указанным ID
+func addSubtask(tl *TodoList, id int, content string) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	if err := validateContent(content); err != nil {
+		return err
+	}
+
+	tl.Tasks[index].Subtasks = append(tl.Tasks[index].Subtasks, Subtask{Content: content})
+	return nil
+}
+
+// toggleSubtask изменяет статус подзадачи с номером n (начиная с 1)
+func toggleSubtask(tl *TodoList, id, n int) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	subtasks := tl.Tasks[index].Subtasks
+	if n < 1 || n > len(subtasks) {
+		return fmt.Errorf(msg("Ошибка: подзадача %d не найдена"), n)
+	}
+
+	subtasks[n-1].Done = !subtasks[n-1].Done
+	return nil
+}
+
+// subtaskProgress возвращает количество выполненных подзадач и их общее число
+func subtaskProgress(task Task) (int, int) {
+	done := 0
+	for _, s := range task.Subtasks {
+		if s.Done {
+			done++
+		}
+	}
+
+	return done, len(task.Subtasks)
+}
+
+// syncParentCompletion отмечает выполненными невыполненные задачи, у которых выполнены все подзадачи
+// Задачи без подзадач не затрагиваются, защищённые без force пропускаются.
+// Возвращает количество отмеченных задач и ID пропущенных
+func syncParentCompletion(tl *TodoList, force bool, now time.Time) (int, []int) {
+	completed := 0
+	var skipped []int
+	for i := range len(tl.Tasks) {
+		task := tl.Tasks[i]
+		if task.Done {
+			continue
+		}
+
+		if done, total := subtaskProgress(task); total == 0 || done < total {
+			continue
+		}
+
+		if protected(task, force) {
+			skipped = append(skipped, task.Id)
+			continue
+		}
+
+		finishTask(tl, i, eventComplete, now)
+		completed++
+	}
+
+	return completed, skipped
+}
+
+// renderTree выводит задачи деревом: под каждой задачей с отступом идут её подзадачи со своими отметками
+// Задачи без подзадач выводятся одной строкой, как в обычном списке
+func renderTree(tasks []Task, w io.Writer, opts DisplayOptions) {
+	if len(tasks) == 0 {
+		fmt.Fprintln(w, msg("Список задач пуст"))
+		return
+	}
+
+	fmt.Fprintln(w, msg("Список задач:"))
+	for _, task := range tasks {
+		fmt.Fprintln(w, formatTaskLine(task, opts))
+		for i, s := range task.Subtasks {
+			mark := " "
+			if s.Done {
+				mark = "x"
+			}
+			fmt.Fprintf(w, "    %d [%s] %s\n", i+1, mark, truncateRunes(s.Content, opts.Truncate))
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSyncParentCompletion(t *testing.T) {
+	tests := []struct {
+		name     string
+		subtasks []Subtask
+		done     bool
+		wantDone bool
+		want     int
+	}{
+		{"all subtasks done", []Subtask{{Content: "a", Done: true}, {Content: "b", Done: true}}, false, true, 1},
+		{"partially done", []Subtask{{Content: "a", Done: true}, {Content: "b"}}, false, false, 0},
+		{"none done", []Subtask{{Content: "a"}}, false, false, 0},
+		{"no subtasks", nil, false, false, 0},
+		{"already done", []Subtask{{Content: "a", Done: true}}, true, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("parent")
+			tl.Tasks[0].Subtasks = tt.subtasks
+			tl.Tasks[0].Done = tt.done
+
+			if got, _ := syncParentCompletion(tl, false, testNow); got != tt.want {
+				t.Errorf("syncParentCompletion = %d, want %d", got, tt.want)
+			}
+			if tl.Tasks[0].Done != tt.wantDone {
+				t.Errorf("Done = %v, want %v", tl.Tasks[0].Done, tt.wantDone)
+			}
+			if tt.want > 0 && tl.Tasks[0].CompletedAt != testNow.Format(timeLayout) {
+				t.Errorf("CompletedAt = %q, want %q", tl.Tasks[0].CompletedAt, testNow.Format(timeLayout))
+			}
+		})
+	}
+}
+
+func TestSyncParentCompletionMixed(t *testing.T) {
+	tl := newTestList("full", "partial", "plain")
+	tl.Tasks[0].Subtasks = []Subtask{{Content: "x", Done: true}}
+	tl.Tasks[1].Subtasks = []Subtask{{Content: "x", Done: true}, {Content: "y"}}
+
+	if got, _ := syncParentCompletion(tl, false, testNow); got != 1 {
+		t.Fatalf("syncParentCompletion = %d, want 1", got)
+	}
+	if !tl.Tasks[0].Done || tl.Tasks[1].Done || tl.Tasks[2].Done {
+		t.Errorf("done flags = %v %v %v, want true false false", tl.Tasks[0].Done, tl.Tasks[1].Done, tl.Tasks[2].Done)
+	}
+}
+
+func TestRenderTree(t *testing.T) {
+	tl := newTestList("parent", "plain")
+	tl.Tasks[0].Subtasks = []Subtask{{Content: "first", Done: true}, {Content: "second"}}
+
+	var buf bytes.Buffer
+	renderTree(tl.Tasks, &buf, DisplayOptions{})
+
+	want := "Список задач:\n" +
+		formatTaskLine(tl.Tasks[0], DisplayOptions{}) + "\n" +
+		"    1 [x] first\n" +
+		"    2 [ ] second\n" +
+		formatTaskLine(tl.Tasks[1], DisplayOptions{}) + "\n"
+	if buf.String() != want {
+		t.Errorf("tree =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	renderTree(nil, &buf, DisplayOptions{})
+	if buf.String() != "Список задач пуст\n" {
+		t.Errorf("empty tree = %q", buf.String())
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const untaggedGroup = "" // Ключ группы задач без тегов
+
+// parseTags разбирает строку тегов, разделённых запятыми
+// Пустые значения и повторы (без учета регистра) отбрасываются
+func parseTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		tags = appendTag(tags, tag)
+	}
+
+	return tags
+}
+
+// appendTag добавляет тег к списку, если его там ещё нет
+func appendTag(tags []string, tag string) []string {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return tags
+	}
+
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return tags
+		}
+	}
+
+	return append(tags, tag)
+}
+
+// hasTag проверяет, есть ли у задачи указанный тег (без учета регистра)
+func hasTag(task Task, tag string) bool {
+	tag = strings.TrimSpace(tag)
+	for _, t := range task.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setTags заменяет набор тегов задачи и возвращает получившийся набор
+// Теги очищаются от пробелов, повторы отбрасываются
+func setTags(tl *TodoList, id int, tags []string) ([]string, error) {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return nil, errors.New(msg("Задача не найдена"))
+	}
+
+	var result []string
+	for _, tag := range tags {
+		result = appendTag(result, tag)
+	}
+
+	tl.Tasks[index].Tags = result
+	return result, nil
+}
+
+// addTag добавляет тег к задаче, если его ещё нет, и возвращает получившийся набор
+func addTag(tl *TodoList, id int, tag string) ([]string, error) {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return nil, errors.New(msg("Задача не найдена"))
+	}
+
+	tl.Tasks[index].Tags = appendTag(tl.Tasks[index].Tags, tag)
+	return tl.Tasks[index].Tags, nil
+}
+
+// addTagWhere добавляет тег всем задачам, подходящим под условие match
+// Возвращает количество задач, у которых тег появился
+func addTagWhere(tl *TodoList, tag string, match func(Task) bool) int {
+	changed := 0
+	for i := range tl.Tasks {
+		if !match(tl.Tasks[i]) {
+			continue
+		}
+
+		before := len(tl.Tasks[i].Tags)
+		tl.Tasks[i].Tags = appendTag(tl.Tasks[i].Tags, tag)
+		if len(tl.Tasks[i].Tags) != before {
+			changed++
+		}
+	}
+
+	return changed
+}
+
+// removeTag удаляет тег у задачи (без учета регистра) и возвращает получившийся набор
+// Удаление отсутствующего тега ничего не меняет
+func removeTag(tl *TodoList, id int, tag string) ([]string, error) {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return nil, errors.New(msg("Задача не найдена"))
+	}
+
+	tag = strings.TrimSpace(tag)
+	var result []string
+	for _, t := range tl.Tasks[index].Tags {
+		if !strings.EqualFold(t, tag) {
+			result = append(result, t)
+		}
+	}
+
+	tl.Tasks[index].Tags = result
+	return result, nil
+}
+
+// retagTask заменяет у задачи тег old на new (без учета регистра), сохраняя позицию тега
+// Если new уже есть у задачи, повтор отбрасывается. Ошибка, если у задачи нет тега old
+func retagTask(tl *TodoList, id int, old, new string) error {
+	index := findTaskIndex(tl, id)
+	if index == -1 {
+		return errors.New(msg("Задача не найдена"))
+	}
+
+	task := &tl.Tasks[index]
+	old, new = strings.TrimSpace(old), strings.TrimSpace(new)
+	if new == "" {
+		return errors.New(msg("Ошибка: новый тег не может быть пустым"))
+	}
+	if !hasTag(*task, old) {
+		return fmt.Errorf(msg("Ошибка: у задачи #%d нет тега %s"), id, old)
+	}
+
+	var tags []string
+	for _, t := range task.Tags {
+		if strings.EqualFold(t, old) {
+			t = new
+		}
+		tags = appendTag(tags, t)
+	}
+
+	task.Tags = tags
+	return nil
+}
+
+// formatTags возвращает теги через запятую или прочерк, если тегов нет
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return "—"
+	}
+
+	return strings.Join(tags, ", ")
+}
+
+// deleteByTag удаляет все задачи с указанным тегом и возвращает их количество
+// Защищённые задачи без force остаются, их ID возвращаются отдельно. Счётчик NextId при этом не изменяется
+func deleteByTag(tl *TodoList, tag string, force bool) (int, []int) {
+	kept := tl.Tasks[:0]
+	var skipped []int
+	for _, task := range tl.Tasks {
+		switch {
+		case !hasTag(task, tag):
+			kept = append(kept, task)
+		case protected(task, force):
+			kept = append(kept, task)
+			skipped = append(skipped, task.Id)
+		}
+	}
+
+	removed := len(tl.Tasks) - len(kept)
+	tl.Tasks = kept
+	return removed, skipped
+}
+
+// groupByTag группирует задачи по тегам (без учета регистра)
+// Задача с несколькими тегами попадает в каждую группу, задачи без тегов — в группу untaggedGroup
+func groupByTag(tl *TodoList) map[string][]Task {
+	groups := make(map[string][]Task)
+	for _, task := range tl.Tasks {
+		if len(task.Tags) == 0 {
+			groups[untaggedGroup] = append(groups[untaggedGroup], task)
+			continue
+		}
+
+		for _, tag := range task.Tags {
+			key := strings.ToLower(tag)
+			groups[key] = append(groups[key], task)
+		}
+	}
+
+	return groups
+}
+
+// canonicalizeTags приводит теги всех задач к нижнему регистру и убирает повторы внутри задачи
+// Возвращает количество задач, у которых набор тегов изменился
+func canonicalizeTags(tl *TodoList) int {
+	changed := 0
+	for i := range tl.Tasks {
+		task := &tl.Tasks[i]
+		var tags []string
+		for _, tag := range task.Tags {
+			tags = appendTag(tags, strings.ToLower(tag))
+		}
+
+		if !slices.Equal(tags, task.Tags) {
+			task.Tags = tags
+			changed++
+		}
+	}
+	return changed
+}
+
+// tagCounts считает задачи по тегам (без учета регистра)
+// Задачи без тегов не учитываются
+func tagCounts(tl *TodoList) map[string]int {
+	counts := make(map[string]int)
+	for _, task := range tl.Tasks {
+		for _, tag := range task.Tags {
+			counts[strings.ToLower(tag)]++
+		}
+	}
+	return counts
+}
+
+// unusedTags возвращает теги из known, которые не встречаются ни в одной задаче
+// Теги сравниваются без учета регистра, порядок known сохраняется
+func unusedTags(tl *TodoList, known []string) []string {
+	counts := tagCounts(tl)
+	var unused []string
+	for _, tag := range known {
+		if counts[strings.ToLower(tag)] == 0 {
+			unused = append(unused, tag)
+		}
+	}
+	return unused
+}
+
+// limitPerTag оставляет в каждой группе не больше n первых задач, включая группу без тегов
+// При n <= 0 группы возвращаются без изменений
+func limitPerTag(groups map[string][]Task, n int) map[string][]Task {
+	if n <= 0 {
+		return groups
+	}
+
+	limited := make(map[string][]Task, len(groups))
+	for tag, tasks := range groups {
+		if len(tasks) > n {
+			tasks = tasks[:n]
+		}
+		limited[tag] = tasks
+	}
+
+	return limited
+}
+
+// renderGroups выводит группы задач под заголовками тегов в алфавитном порядке
+// Группа задач без тегов выводится последней
+func renderGroups(groups map[string][]Task, w io.Writer, opts DisplayOptions) {
+	if len(groups) == 0 {
+		fmt.Fprintln(w, msg("Список задач пуст"))
+		return
+	}
+
+	var tags []string
+	for tag := range groups {
+		if tag != untaggedGroup {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+
+	for _, tag := range tags {
+		fmt.Fprintf(w, "#%s:\n", tag)
+		for _, task := range groups[tag] {
+			fmt.Fprintf(w, "  %s\n", formatTaskLine(task, opts))
+		}
+	}
+
+	if tasks, ok := groups[untaggedGroup]; ok {
+		fmt.Fprintln(w, msg("Без тегов:"))
+		for _, task := range tasks {
+			fmt.Fprintf(w, "  %s\n", formatTaskLine(task, opts))
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// taskIds возвращает ID задач в порядке списка
+func taskIds(tasks []Task) []int {
+	ids := []int{}
+	for _, task := range tasks {
+		ids = append(ids, task.Id)
+	}
+	return ids
+}
+
+func TestDeleteByTag(t *testing.T) {
+	tl := newTestList("a", "b", "c", "d")
+	tl.Tasks[0].Tags = []string{"Work"}
+	tl.Tasks[1].Tags = []string{"home", "work"}
+	tl.Tasks[2].Tags = []string{"home"}
+
+	removed, _ := deleteByTag(tl, "WORK", false)
+
+	if removed != 2 {
+		t.Errorf("removed = %d, want 2", removed)
+	}
+	if got, want := taskIds(tl.Tasks), []int{3, 4}; !slices.Equal(got, want) {
+		t.Errorf("remaining = %v, want %v", got, want)
+	}
+	if tl.NextId != 5 {
+		t.Errorf("NextId = %d, want 5", tl.NextId)
+	}
+}
+
+func TestGroupByTag(t *testing.T) {
+	tl := newTestList("a", "b", "c")
+	tl.Tasks[0].Tags = []string{"work", "Home"}
+	tl.Tasks[1].Tags = []string{"home"}
+
+	groups := groupByTag(tl)
+
+	want := map[string][]int{"work": {1}, "home": {1, 2}, untaggedGroup: {3}}
+	if len(groups) != len(want) {
+		t.Fatalf("groups = %v", groups)
+	}
+	for tag, ids := range want {
+		if got := taskIds(groups[tag]); !slices.Equal(got, ids) {
+			t.Errorf("group %q = %v, want %v", tag, got, ids)
+		}
+	}
+}
+
+func TestGroupByTagNoUntagged(t *testing.T) {
+	tl := newTestList("a")
+	tl.Tasks[0].Tags = []string{"work"}
+
+	if _, ok := groupByTag(tl)[untaggedGroup]; ok {
+		t.Error("untagged group created for a fully tagged list")
+	}
+}
+
+func TestRenderGroupsOrder(t *testing.T) {
+	tl := newTestList("a", "b", "c")
+	tl.Tasks[0].Tags = []string{"zeta"}
+	tl.Tasks[1].Tags = []string{"alpha", "zeta"}
+
+	var buf bytes.Buffer
+	renderGroups(groupByTag(tl), &buf, DisplayOptions{})
+	out := buf.String()
+
+	alpha, zeta, untagged := strings.Index(out, "#alpha:"), strings.Index(out, "#zeta:"), strings.Index(out, "Без тегов:")
+	if alpha < 0 || zeta < 0 || untagged < 0 || !(alpha < zeta && zeta < untagged) {
+		t.Errorf("unexpected group order:\n%s", out)
+	}
+	if strings.Count(out, "2 [ ], b") != 2 {
+		t.Errorf("multi-tag task not listed under each tag:\n%s", out)
+	}
+}
+
+func TestSetTags(t *testing.T) {
+	tl := newTestList("a")
+	tl.Tasks[0].Tags = []string{"old"}
+
+	got, err := setTags(tl, 1, []string{" work ", "home", "Work", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"work", "home"}; !slices.Equal(got, want) || !slices.Equal(tl.Tasks[0].Tags, want) {
+		t.Errorf("setTags = %q, task tags = %q, want %q", got, tl.Tasks[0].Tags, want)
+	}
+}
+
+func TestAddRemoveTag(t *testing.T) {
+	tests := []struct {
+		name string
+		op   func(tl *TodoList) ([]string, error)
+		want []string
+	}{
+		{"add new", func(tl *TodoList) ([]string, error) { return addTag(tl, 1, "urgent") }, []string{"work", "home", "urgent"}},
+		{"add duplicate", func(tl *TodoList) ([]string, error) { return addTag(tl, 1, " WORK ") }, []string{"work", "home"}},
+		{"remove present", func(tl *TodoList) ([]string, error) { return removeTag(tl, 1, "Home") }, []string{"work"}},
+		{"remove absent", func(tl *TodoList) ([]string, error) { return removeTag(tl, 1, "urgent") }, []string{"work", "home"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("a")
+			tl.Tasks[0].Tags = []string{"work", "home"}
+
+			got, err := tt.op(tl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) || !slices.Equal(tl.Tasks[0].Tags, tt.want) {
+				t.Errorf("tags = %q, task tags = %q, want %q", got, tl.Tasks[0].Tags, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagsMissingTask(t *testing.T) {
+	tl := newTestList("a")
+	if _, err := setTags(tl, 2, []string{"x"}); err == nil {
+		t.Error("setTags: expected an error")
+	}
+	if _, err := addTag(tl, 2, "x"); err == nil {
+		t.Error("addTag: expected an error")
+	}
+	if _, err := removeTag(tl, 2, "x"); err == nil {
+		t.Error("removeTag: expected an error")
+	}
+}
+
+func TestLimitPerTag(t *testing.T) {
+	tl := newTestList("a", "b", "c", "d", "e", "f")
+	for i := range 3 {
+		tl.Tasks[i].Tags = []string{"work"}
+	}
+	tl.Tasks[3].Tags = []string{"home", "work"}
+
+	groups := limitPerTag(groupByTag(tl), 2)
+
+	want := map[string][]int{"work": {1, 2}, "home": {4}, untaggedGroup: {5, 6}}
+	for tag, ids := range want {
+		if got := taskIds(groups[tag]); !slices.Equal(got, ids) {
+			t.Errorf("group %q = %v, want %v", tag, got, ids)
+		}
+	}
+
+	if got := taskIds(limitPerTag(groupByTag(tl), 1)[untaggedGroup]); !slices.Equal(got, []int{5}) {
+		t.Errorf("untagged group = %v, want [5]", got)
+	}
+	if got := taskIds(limitPerTag(groupByTag(tl), 0)["work"]); len(got) != 4 {
+		t.Errorf("limit 0 work group = %v, want all 4 tasks", got)
+	}
+}
+
+func TestAddTagWhere(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     FilterOptions
+		want     int
+		wantTags map[int][]string
+	}{
+		{"by status", FilterOptions{Status: statusPending}, 2, map[int][]string{
+			1: {"work", "import"}, 2: {"import"}, 3: {"Import"}, 4: nil,
+		}},
+		{"by search", FilterOptions{Search: "MILK"}, 1, map[int][]string{
+			1: {"work", "import"}, 2: nil, 3: {"Import"}, 4: nil,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("buy milk", "call mom", "more milk", "done")
+			tl.Tasks[0].Tags = []string{"work"}
+			tl.Tasks[2].Tags = []string{"Import"}
+			markDone(&tl.Tasks[3], testNow)
+
+			got := addTagWhere(tl, "import", func(task Task) bool { return matchFilters(task, tt.opts) })
+
+			if got != tt.want {
+				t.Errorf("changed = %d, want %d", got, tt.want)
+			}
+			for _, task := range tl.Tasks {
+				if want := tt.wantTags[task.Id]; !slices.Equal(task.Tags, want) {
+					t.Errorf("task #%d tags = %q, want %q", task.Id, task.Tags, want)
+				}
+			}
+		})
+	}
+}
+
+func TestUnusedTags(t *testing.T) {
+	tl := newTestList("a", "b", "c")
+	tl.Tasks[0].Tags = []string{"Work"}
+	tl.Tasks[1].Tags = []string{"home", "work"}
+
+	tests := []struct {
+		name  string
+		known []string
+		want  []string
+	}{
+		{"some unused", []string{"zeta", "work", "Errand", "HOME"}, []string{"zeta", "Errand"}},
+		{"all used", []string{"home", "work"}, nil},
+		{"none known", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unusedTags(tl, tt.known); !slices.Equal(got, tt.want) {
+				t.Errorf("unusedTags = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeTags(t *testing.T) {
+	tl := newTestList("a", "b", "c", "d")
+	tl.Tasks[0].Tags = []string{"Work", "work", "HOME"}
+	tl.Tasks[1].Tags = []string{"WORK"}
+	tl.Tasks[2].Tags = []string{"home", "work"}
+
+	if got := canonicalizeTags(tl); got != 2 {
+		t.Errorf("canonicalizeTags = %d, want 2", got)
+	}
+
+	want := [][]string{{"work", "home"}, {"work"}, {"home", "work"}, nil}
+	for i, task := range tl.Tasks {
+		if !slices.Equal(task.Tags, want[i]) {
+			t.Errorf("task #%d tags = %q, want %q", task.Id, task.Tags, want[i])
+		}
+	}
+
+	if got := taskIds(applyFilters(tl, FilterOptions{Tag: "work"})); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("work filter = %v, want [1 2 3]", got)
+	}
+	if got := canonicalizeTags(tl); got != 0 {
+		t.Errorf("second canonicalizeTags = %d, want 0", got)
+	}
+}
+
+func TestRetagTask(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int
+		old     string
+		new     string
+		want    []string
+		wantErr bool
+	}{
+		{"success", 1, "WORK", " job ", []string{"job", "home"}, false},
+		{"deduplicates", 1, "work", "Home", []string{"Home"}, false},
+		{"missing old tag", 1, "errand", "job", []string{"work", "home"}, true},
+		{"empty new tag", 1, "work", " ", []string{"work", "home"}, true},
+		{"missing task", 3, "work", "job", []string{"work", "home"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("a", "b")
+			tl.Tasks[0].Tags = []string{"work", "home"}
+			tl.Tasks[1].Tags = []string{"work"}
+
+			err := retagTask(tl, tt.id, tt.old, tt.new)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("retagTask error = %v, want error %v", err, tt.wantErr)
+			}
+			if !slices.Equal(tl.Tasks[0].Tags, tt.want) {
+				t.Errorf("task #1 tags = %q, want %q", tl.Tasks[0].Tags, tt.want)
+			}
+			if !slices.Equal(tl.Tasks[1].Tags, []string{"work"}) {
+				t.Errorf("task #2 tags changed: %q", tl.Tasks[1].Tags)
+			}
+		})
+	}
+}
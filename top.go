@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// kv — пара «ключ — количество» для рейтингов
+type kv struct {
+	Key   string
+	Count int
+}
+
+// topN возвращает n пар с наибольшим количеством, от большего к меньшему
+// При равном количестве пары идут по алфавиту; при n <= 0 возвращаются все пары
+func topN(counts map[string]int, n int) []kv {
+	pairs := make([]kv, 0, len(counts))
+	for key, count := range counts {
+		pairs = append(pairs, kv{Key: key, Count: count})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Count != pairs[j].Count {
+			return pairs[i].Count > pairs[j].Count
+		}
+		return pairs[i].Key < pairs[j].Key
+	})
+
+	if n > 0 && len(pairs) > n {
+		pairs = pairs[:n]
+	}
+	return pairs
+}
+
+// printTop выводит рейтинг с заголовком, для пустого рейтинга — сообщение empty
+func printTop(pairs []kv, title, empty string, w io.Writer) {
+	if len(pairs) == 0 {
+		fmt.Fprintln(w, empty)
+		return
+	}
+
+	fmt.Fprintln(w, title)
+	for i, p := range pairs {
+		fmt.Fprintf(w, "%d. %s: %d\n", i+1, p.Key, p.Count)
+	}
+}
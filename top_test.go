@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+func TestTopN(t *testing.T) {
+	counts := map[string]int{"work": 3, "home": 1, "alpha": 1, "zeta": 3, "misc": 2}
+
+	tests := []struct {
+		name string
+		n    int
+		want []kv
+	}{
+		{"ties alphabetical", 3, []kv{{"work", 3}, {"zeta", 3}, {"misc", 2}}},
+		{"n larger than map", 10, []kv{{"work", 3}, {"zeta", 3}, {"misc", 2}, {"alpha", 1}, {"home", 1}}},
+		{"zero returns all", 0, []kv{{"work", 3}, {"zeta", 3}, {"misc", 2}, {"alpha", 1}, {"home", 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := topN(counts, tt.n); !slices.Equal(got, tt.want) {
+				t.Errorf("topN(%d) = %v, want %v", tt.n, got, tt.want)
+			}
+		})
+	}
+
+	if got := topN(map[string]int{}, 3); len(got) != 0 {
+		t.Errorf("topN(empty) = %v", got)
+	}
+}
+
+func TestTopTagsAndAssignees(t *testing.T) {
+	tl := newTestList("a", "b", "c", "d")
+	tl.Tasks[0].Tags = []string{"Work", "home"}
+	tl.Tasks[1].Tags = []string{"work"}
+	tl.Tasks[2].Tags = []string{"errand"}
+	tl.Tasks[0].Assignee = "ivan"
+	tl.Tasks[1].Assignee = "anna"
+	tl.Tasks[2].Assignee = "ivan"
+
+	if got, want := topN(tagCounts(tl), 2), []kv{{"work", 2}, {"errand", 1}}; !slices.Equal(got, want) {
+		t.Errorf("top tags = %v, want %v", got, want)
+	}
+	if got, want := topN(assigneeCounts(tl), 0), []kv{{"ivan", 2}, {"anna", 1}}; !slices.Equal(got, want) {
+		t.Errorf("top assignees = %v, want %v", got, want)
+	}
+}
+
+func TestPrintTop(t *testing.T) {
+	var buf bytes.Buffer
+	printTop([]kv{{"work", 2}, {"home", 1}}, "Теги:", "Нет тегов", &buf)
+	if want := "Теги:\n1. work: 2\n2. home: 1\n"; buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	printTop(nil, "Теги:", "Нет тегов", &buf)
+	if buf.String() != "Нет тегов\n" {
+		t.Errorf("empty output = %q", buf.String())
+	}
+}
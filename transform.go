@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// applyTransform применяет преобразование к тексту задач, подходящих под условие match
+// Задачи, текст которых после преобразования стал бы некорректным или совпал бы с текстом другой задачи,
+// не изменяются. Возвращает количество изменённых задач и ID пропущенных
+func applyTransform(tl *TodoList, match func(Task) bool, transform func(string) string, now time.Time) (int, []int) {
+	changed := 0
+	var skipped []int
+	for i := range tl.Tasks {
+		if !match(tl.Tasks[i]) {
+			continue
+		}
+
+		content := transform(tl.Tasks[i].Content)
+		if content == tl.Tasks[i].Content {
+			continue
+		}
+
+		candidate := tl.Tasks[i]
+		candidate.Content = content
+		if err := validateTask(tl, candidate, false); err != nil {
+			skipped = append(skipped, tl.Tasks[i].Id)
+			continue
+		}
+
+		tl.Tasks[i].Content = content
+		recordEvent(&tl.Tasks[i], eventEdit, now)
+		changed++
+	}
+
+	return changed, skipped
+}
+
+// affix возвращает преобразование, добавляющее к тексту префикс и суффикс
+func affix(prefix, suffix string) func(string) string {
+	return func(s string) string {
+		return prefix + s + suffix
+	}
+}
+
+// regexReplace возвращает преобразование, заменяющее совпадения с регулярным выражением pattern
+// В replacement можно ссылаться на группы: $1, ${name}
+func regexReplace(pattern, replacement string) (func(string) string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf(msg("Ошибка: не верное регулярное выражение: %w"), err)
+	}
+
+	return func(s string) string {
+		return re.ReplaceAllString(s, replacement)
+	}, nil
+}
+
+// titleCase делает первую букву каждого слова заглавной, а остальные — строчными
+func titleCase(s string) string {
+	runes := []rune(s)
+	start := true
+	for i, r := range runes {
+		if unicode.IsSpace(r) {
+			start = true
+			continue
+		}
+		if start {
+			runes[i] = unicode.ToUpper(r)
+		} else {
+			runes[i] = unicode.ToLower(r)
+		}
+		start = false
+	}
+	return string(runes)
+}
+
+// sentenceCase делает заглавной только первую букву текста
+func sentenceCase(s string) string {
+	runes := []rune(strings.ToLower(s))
+	for i, r := range runes {
+		if unicode.IsLetter(r) {
+			runes[i] = unicode.ToUpper(r)
+			break
+		}
+	}
+	return string(runes)
+}
+
+// caseTransform возвращает преобразование регистра по названию: title, sentence или lower
+func caseTransform(mode string) (func(string) string, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "title":
+		return titleCase, nil
+	case "sentence":
+		return sentenceCase, nil
+	case "lower":
+		return strings.ToLower, nil
+	default:
+		return nil, fmt.Errorf(msg("Ошибка: не верный регистр %q (ожидается title, sentence или lower)"), mode)
+	}
+}
+
+// parseIdRange разбирает диапазон ID вида "3-7" или одиночный ID "5"
+func parseIdRange(s string) (int, int, error) {
+	fromStr, toStr, found := strings.Cut(s, "-")
+	if !found {
+		toStr = fromStr
+	}
+
+	from, err := strconv.Atoi(strings.TrimSpace(fromStr))
+	if err != nil {
+		return 0, 0, fmt.Errorf(msg("Ошибка: не верный диапазон %q"), s)
+	}
+
+	to, err := strconv.Atoi(strings.TrimSpace(toStr))
+	if err != nil || to < from {
+		return 0, 0, fmt.Errorf(msg("Ошибка: не верный диапазон %q"), s)
+	}
+
+	return from, to, nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestApplyTransformAffix(t *testing.T) {
+	long := strings.Repeat("x", maxTaskLength-2)
+	firstTwo := func(task Task) bool { return task.Id <= 2 }
+	allButThird := func(task Task) bool { return task.Id != 3 }
+
+	tests := []struct {
+		name        string
+		match       func(Task) bool
+		prefix      string
+		suffix      string
+		wantContent []string
+		wantChanged int
+		wantSkipped []int
+	}{
+		{"prefix", firstTwo, "[Q3] ", "", []string{"[Q3] a", "[Q3] b", "c", long}, 2, nil},
+		{"suffix", firstTwo, "", " (офис)", []string{"a (офис)", "b (офис)", "c", long}, 2, nil},
+		{"length limit skip", allButThird, "", "!!!", []string{"a!!!", "b!!!", "c", long}, 2, []int{4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList("a", "b", "c", long)
+			changed, skipped := applyTransform(tl, tt.match, affix(tt.prefix, tt.suffix), testNow)
+			if changed != tt.wantChanged || !slices.Equal(skipped, tt.wantSkipped) {
+				t.Errorf("applyTransform = %d, %v; want %d, %v", changed, skipped, tt.wantChanged, tt.wantSkipped)
+			}
+			for i, want := range tt.wantContent {
+				if got := tl.Tasks[i].Content; got != want {
+					t.Errorf("task #%d content = %q, want %q", tl.Tasks[i].Id, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseIdRange(t *testing.T) {
+	tests := []struct {
+		in       string
+		from, to int
+		wantErr  bool
+	}{
+		{"3-7", 3, 7, false},
+		{"5", 5, 5, false},
+		{" 2 - 4 ", 2, 4, false},
+		{"7-3", 0, 0, true},
+		{"a-b", 0, 0, true},
+		{"", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		from, to, err := parseIdRange(tt.in)
+		if (err != nil) != tt.wantErr || from != tt.from || to != tt.to {
+			t.Errorf("parseIdRange(%q) = %d, %d, %v", tt.in, from, to, err)
+		}
+	}
+}
+
+func TestCaseTransforms(t *testing.T) {
+	tests := []struct {
+		mode string
+		in   string
+		want string
+	}{
+		{"title", "купить МОЛОКО и хлеб", "Купить Молоко И Хлеб"},
+		{"title", "ёлка  в\tлесу", "Ёлка  В\tЛесу"},
+		{"sentence", "КУПИТЬ молоко", "Купить молоко"},
+		{"sentence", "  «ёлка» в лесу", "  «Ёлка» в лесу"},
+		{"lower", "Купить МОЛОКО", "купить молоко"},
+		{"Title", "buy milk", "Buy Milk"},
+	}
+
+	for _, tt := range tests {
+		transform, err := caseTransform(tt.mode)
+		if err != nil {
+			t.Fatalf("caseTransform(%q): %v", tt.mode, err)
+		}
+		if got := transform(tt.in); got != tt.want {
+			t.Errorf("%s(%q) = %q, want %q", tt.mode, tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := caseTransform("upper"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
+
+func TestRetitleSkipsInvalid(t *testing.T) {
+	// Заглавная «Ɐ» занимает в UTF-8 больше байт, чем строчная «ɐ», и текст превысил бы лимит
+	long := strings.Repeat("ɐ", maxTaskLength/2)
+	tl := newTestList("купить молоко", long, "Уже Готово")
+	all := func(Task) bool { return true }
+
+	changed, skipped := applyTransform(tl, all, titleCase, testNow)
+
+	if changed != 1 || !slices.Equal(skipped, []int{2}) {
+		t.Errorf("applyTransform = %d, %v; want 1, [2]", changed, skipped)
+	}
+	if tl.Tasks[0].Content != "Купить Молоко" || tl.Tasks[1].Content != long {
+		t.Errorf("contents = %q, %q", tl.Tasks[0].Content, tl.Tasks[1].Content)
+	}
+}
+
+func TestRegexReplace(t *testing.T) {
+	long := strings.Repeat("x", maxTaskLength-1)
+	all := func(Task) bool { return true }
+
+	tests := []struct {
+		name        string
+		pattern     string
+		replacement string
+		wantContent []string
+		wantChanged int
+		wantSkipped []int
+	}{
+		{"capture groups", `(\w+)@(\w+)`, "$2:$1", []string{"call bob:anna", "b", "c", long}, 1, nil},
+		{"named group", `^(?P<word>b)$`, "${word}${word}", []string{"call anna@bob", "bb", "c", long}, 1, nil},
+		{"empty result skip", `^c$`, "", []string{"call anna@bob", "b", "c", long}, 0, []int{3}},
+		{"length limit skip", `x$`, "yyy", []string{"call anna@bob", "b", "c", long}, 0, []int{4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transform, err := regexReplace(tt.pattern, tt.replacement)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tl := newTestList("call anna@bob", "b", "c", long)
+			changed, skipped := applyTransform(tl, all, transform, testNow)
+			if changed != tt.wantChanged || !slices.Equal(skipped, tt.wantSkipped) {
+				t.Errorf("applyTransform = %d, %v; want %d, %v", changed, skipped, tt.wantChanged, tt.wantSkipped)
+			}
+			for i, want := range tt.wantContent {
+				if got := tl.Tasks[i].Content; got != want {
+					t.Errorf("task #%d content = %q, want %q", tl.Tasks[i].Id, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRegexReplaceInvalidPattern(t *testing.T) {
+	if _, err := regexReplace("(", "x"); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+// mustRegexReplace возвращает преобразование regexReplace или завершает тест с ошибкой
+func mustRegexReplace(t *testing.T, pattern, replacement string) func(string) string {
+	t.Helper()
+	transform, err := regexReplace(pattern, replacement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return transform
+}
+
+func TestApplyTransformCollision(t *testing.T) {
+	all := func(Task) bool { return true }
+
+	tests := []struct {
+		name        string
+		contents    []string
+		transform   func(string) string
+		wantContent []string
+		wantChanged int
+		wantSkipped []int
+	}{
+		{"normalize onto existing", []string{"Buy milk", "Buy  milk", " call mom "}, normalizeContent,
+			[]string{"Buy milk", "Buy  milk", "call mom"}, 1, []int{2}},
+		{"normalize two onto each other", []string{"a  b", "a   b"}, normalizeContent,
+			[]string{"a b", "a   b"}, 1, []int{2}},
+		{"title case onto existing", []string{"Buy Milk", "buy milk"}, titleCase,
+			[]string{"Buy Milk", "buy milk"}, 0, []int{2}},
+		{"replace onto existing", []string{"TASK-1", "ТЗ-1", "ТЗ-2"}, mustRegexReplace(t, `ТЗ-(\d+)`, "TASK-$1"),
+			[]string{"TASK-1", "ТЗ-1", "TASK-2"}, 1, []int{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestList(tt.contents...)
+			changed, skipped := applyTransform(tl, all, tt.transform, testNow)
+			if changed != tt.wantChanged || !slices.Equal(skipped, tt.wantSkipped) {
+				t.Errorf("applyTransform = %d, %v; want %d, %v", changed, skipped, tt.wantChanged, tt.wantSkipped)
+			}
+			for i, want := range tt.wantContent {
+				if got := tl.Tasks[i].Content; got != want {
+					t.Errorf("task #%d content = %q, want %q", tl.Tasks[i].Id, got, want)
+				}
+			}
+		})
+	}
+}